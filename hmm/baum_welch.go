@@ -0,0 +1,178 @@
+package hmm
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pforderique/markov_chain/linalg"
+)
+
+// TrainOptions configures Model.BaumWelch. The zero value uses the default
+// tolerance and iteration limit.
+type TrainOptions struct {
+	// Tolerance is the increase in total log-likelihood between iterations
+	// below which training is considered converged. Defaults to 1e-6.
+	Tolerance float64
+	// MaxIterations bounds the number of EM iterations. Defaults to 100.
+	MaxIterations int
+}
+
+// TrainResult is a model trained by Baum-Welch, along with how training
+// went.
+type TrainResult struct {
+	Model *Model
+	// LogLikelihood is the total log-likelihood of the training sequences
+	// under Model.
+	LogLikelihood float64
+	// Iterations is the number of EM iterations run.
+	Iterations int
+	// Converged reports whether the log-likelihood stopped improving by more
+	// than the tolerance.
+	Converged bool
+}
+
+// BaumWelch trains a model on the observation sequences by expectation
+// maximization, starting from m, which is left unchanged. No iteration
+// decreases the total log-likelihood. States that are never visited
+// keep their transition and emission probabilities, and transitions or
+// emissions with probability 0 in m stay at 0. If the iteration limit is
+// reached, the last model is returned with Converged set to false.
+func (m *Model) BaumWelch(sequences [][]int, opts *TrainOptions) (*TrainResult, error) {
+	if opts == nil {
+		opts = &TrainOptions{}
+	}
+	tol := opts.Tolerance
+	if tol == 0 {
+		tol = 1e-6
+	}
+	maxIter := opts.MaxIterations
+	if maxIter == 0 {
+		maxIter = 100
+	}
+	if len(sequences) == 0 {
+		return nil, fmt.Errorf("no observation sequences given")
+	}
+	for _, obs := range sequences {
+		if err := m.checkObservations(obs); err != nil {
+			return nil, err
+		}
+	}
+
+	current := m
+	stats, err := current.expectation(sequences)
+	if err != nil {
+		return nil, err
+	}
+	result := &TrainResult{Model: current, LogLikelihood: stats.logLikelihood}
+	for result.Iterations < maxIter {
+		next, err := current.maximization(stats)
+		if err != nil {
+			return nil, err
+		}
+		nextStats, err := next.expectation(sequences)
+		if err != nil {
+			return nil, err
+		}
+		result.Iterations++
+		improvement := nextStats.logLikelihood - stats.logLikelihood
+		current, stats = next, nextStats
+		result.Model, result.LogLikelihood = current, stats.logLikelihood
+		if improvement < tol {
+			result.Converged = true
+			break
+		}
+	}
+	return result, nil
+}
+
+// sufficientStats are the expected counts gathered by the E-step
+type sufficientStats struct {
+	initial       []float64 // expected starts in each state
+	transitions   []float64 // expected i -> j transitions, N×N
+	emissions     []float64 // expected emissions of k from i, N×M
+	logLikelihood float64
+}
+
+// expectation runs forward-backward on every sequence and accumulates the
+// expected counts
+func (m *Model) expectation(sequences [][]int) (*sufficientStats, error) {
+	n, k := m.NumStates(), m.NumSymbols()
+	a, b := m.params()
+	stats := &sufficientStats{
+		initial:     make([]float64, n),
+		transitions: make([]float64, n*n),
+		emissions:   make([]float64, n*k),
+	}
+
+	for _, obs := range sequences {
+		alpha, scales, err := m.forward(obs, a, b)
+		if err != nil {
+			return nil, err
+		}
+		beta := m.backward(obs, scales, a, b)
+		stats.logLikelihood += logSum(scales)
+
+		for t, o := range obs {
+			for i := 0; i < n; i++ {
+				gamma := alpha[t*n+i] * beta[t*n+i]
+				stats.emissions[i*k+o] += gamma
+				if t == 0 {
+					stats.initial[i] += gamma
+				}
+			}
+			if t == len(obs)-1 {
+				continue
+			}
+			// ξ_t(i, j) = α_t(i)·A[i][j]·B[j][o_{t+1}]·β_{t+1}(j) / c_{t+1}
+			for j := 0; j < n; j++ {
+				w := b[j*k+obs[t+1]] * beta[(t+1)*n+j] / scales[t+1]
+				if w == 0 {
+					continue
+				}
+				for i := 0; i < n; i++ {
+					stats.transitions[i*n+j] += alpha[t*n+i] * a[i*n+j] * w
+				}
+			}
+		}
+	}
+	return stats, nil
+}
+
+// maximization returns the model that maximizes the expected log-likelihood
+// for stats, keeping the rows of m for states with no expected visits
+func (m *Model) maximization(stats *sufficientStats) (*Model, error) {
+	n, k := m.NumStates(), m.NumSymbols()
+	a, b := m.params()
+
+	for i := 0; i < n; i++ {
+		normalizeRow(a[i*n:(i+1)*n], stats.transitions[i*n:(i+1)*n])
+		normalizeRow(b[i*k:(i+1)*k], stats.emissions[i*k:(i+1)*k])
+	}
+	pi := append([]float64(nil), m.Pi...)
+	normalizeRow(pi, stats.initial)
+
+	A, err := linalg.NewSquareMatrixFromData(a, n)
+	if err != nil {
+		return nil, err
+	}
+	B, err := linalg.NewMatrixFromData(b, []int{n, k})
+	if err != nil {
+		return nil, err
+	}
+	return &Model{A, B, pi}, nil
+}
+
+// normalizeRow overwrites dst with counts scaled to sum to 1, unless counts
+// are all zero
+func normalizeRow(dst, counts []float64) {
+	sum := 0.0
+	for _, c := range counts {
+		sum += c
+	}
+	if sum == 0 || math.IsNaN(sum) {
+		return
+	}
+	for i, c := range counts {
+		dst[i] = c / sum
+	}
+}
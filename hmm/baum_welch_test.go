@@ -0,0 +1,102 @@
+package hmm
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// sample draws a sequence of length T of observations from m
+func sample(m *Model, T int, rng *rand.Rand) []int {
+	draw := func(probs func(int) float64, n int) int {
+		u := rng.Float64()
+		for i := 0; i < n-1; i++ {
+			if u -= probs(i); u < 0 {
+				return i
+			}
+		}
+		return n - 1
+	}
+
+	obs := make([]int, T)
+	state := draw(func(i int) float64 { return m.Pi[i] }, m.NumStates())
+	for t := range obs {
+		obs[t] = draw(func(k int) float64 { return m.B.Get(state, k) }, m.NumSymbols())
+		state = draw(func(j int) float64 { return m.A.Get(state, j) }, m.NumStates())
+	}
+	return obs
+}
+
+// TestBaumWelch trains on sequences sampled from a known model and checks
+// that the likelihood never decreases and the parameters are recovered
+func TestBaumWelch(t *testing.T) {
+	t.Parallel()
+	truth := newTestModel(t,
+		[][]float64{{0.9, 0.1}, {0.2, 0.8}},
+		[][]float64{{0.8, 0.1, 0.1}, {0.1, 0.2, 0.7}},
+		[]float64{0.5, 0.5})
+	rng := rand.New(rand.NewPCG(7, 8))
+	var sequences [][]int
+	for i := 0; i < 40; i++ {
+		sequences = append(sequences, sample(truth, 250, rng))
+	}
+
+	initial := newTestModel(t,
+		[][]float64{{0.6, 0.4}, {0.4, 0.6}},
+		[][]float64{{0.5, 0.3, 0.2}, {0.2, 0.3, 0.5}},
+		[]float64{0.5, 0.5})
+
+	previous := math.Inf(-1)
+	for iterations := 1; iterations <= 5; iterations++ {
+		result, err := initial.BaumWelch(sequences,
+			&TrainOptions{MaxIterations: iterations, Tolerance: -1})
+		if err != nil {
+			t.Fatalf("BaumWelch failed: %v", err)
+		}
+		if result.LogLikelihood < previous-1e-9 {
+			t.Fatalf("Log-likelihood decreased from %f to %f at iteration %d",
+				previous, result.LogLikelihood, iterations)
+		}
+		previous = result.LogLikelihood
+	}
+
+	result, err := initial.BaumWelch(sequences, nil)
+	if err != nil {
+		t.Fatalf("BaumWelch failed: %v", err)
+	}
+	if !result.Converged {
+		t.Fatalf("Expected convergence within %d iterations", result.Iterations)
+	}
+	if initial.A.Get(0, 0) != 0.6 {
+		t.Fatalf("BaumWelch modified the initial model")
+	}
+	trueLL := 0.0
+	for _, obs := range sequences {
+		ll, _ := truth.LogLikelihood(obs)
+		trueLL += ll
+	}
+	if result.LogLikelihood < trueLL {
+		t.Fatalf("Trained log-likelihood %f is below that of the true model %f",
+			result.LogLikelihood, trueLL)
+	}
+
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			if got, want := result.Model.A.Get(i, j), truth.A.Get(i, j); math.Abs(got-want) > 0.05 {
+				t.Fatalf("A[%d][%d] = %f, expected about %f", i, j, got, want)
+			}
+		}
+		for k := 0; k < 3; k++ {
+			if got, want := result.Model.B.Get(i, k), truth.B.Get(i, k); math.Abs(got-want) > 0.05 {
+				t.Fatalf("B[%d][%d] = %f, expected about %f", i, k, got, want)
+			}
+		}
+	}
+
+	if _, err := initial.BaumWelch(nil, nil); err == nil {
+		t.Fatalf("Expected error for no sequences")
+	}
+	if _, err := initial.BaumWelch([][]int{{0, 3}}, nil); err == nil {
+		t.Fatalf("Expected error for out-of-range symbol")
+	}
+}
@@ -0,0 +1,235 @@
+// Package hmm implements discrete hidden Markov models: a Markov chain over
+// hidden states, each of which emits an observed symbol.
+package hmm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/pforderique/markov_chain/linalg"
+	"github.com/pforderique/markov_chain/markov"
+)
+
+// ErrImpossible is returned when an observation sequence has zero
+// probability under the model.
+var ErrImpossible = errors.New("observation sequence has zero probability")
+
+// Model is a hidden Markov model with N hidden states and M symbols.
+// A.Get(i, j) is the probability of moving from state i to state j,
+// B.Get(i, k) is the probability of state i emitting symbol k, and Pi[i] is
+// the probability of starting in state i.
+type Model struct {
+	A  *linalg.SquareMatrix
+	B  *linalg.Matrix
+	Pi []float64
+}
+
+// New validates A as an N×N and B as an N×M row-stochastic matrix and pi as
+// a distribution over N states, within markov.Tolerance, and returns a Model
+// over them.
+func New(A *linalg.SquareMatrix, B *linalg.Matrix, pi []float64) (*Model, error) {
+	if A == nil || B == nil {
+		return nil, fmt.Errorf("transition or emission matrix is nil")
+	}
+	n := A.N()
+	dims := B.Dims()
+	if len(dims) != 2 || dims[0] != n {
+		return nil, &linalg.ErrDimensionMismatch{Op: "hmm.New", A: []int{n, n}, B: dims}
+	}
+	if len(pi) != n {
+		return nil, fmt.Errorf(
+			"initial distribution of length %d does not match %d states", len(pi), n)
+	}
+
+	if err := markov.ValidateStochastic(A); err != nil {
+		return nil, err
+	}
+	row := make([]float64, dims[1])
+	for i := 0; i < n; i++ {
+		for k := range row {
+			row[k] = B.Get(i, k)
+		}
+		if err := markov.ValidateDistribution(fmt.Sprintf("row %d of emission matrix", i), row); err != nil {
+			return nil, err
+		}
+	}
+	if err := markov.ValidateDistribution("initial distribution", pi); err != nil {
+		return nil, err
+	}
+	return &Model{A, B, pi}, nil
+}
+
+// NumStates returns the number of hidden states of m.
+func (m *Model) NumStates() int {
+	return m.A.N()
+}
+
+// NumSymbols returns the number of observable symbols of m.
+func (m *Model) NumSymbols() int {
+	return m.B.Dims()[1]
+}
+
+// checkObservations validates obs as a non-empty sequence of symbols
+func (m *Model) checkObservations(obs []int) error {
+	if len(obs) == 0 {
+		return fmt.Errorf("observation sequence is empty")
+	}
+	for t, o := range obs {
+		if o < 0 || o >= m.NumSymbols() {
+			return fmt.Errorf(
+				"observation %d = %d is out of range for %d symbols", t, o, m.NumSymbols())
+		}
+	}
+	return nil
+}
+
+// params copies A and B into row-major slices for the inner loops
+func (m *Model) params() (a, b []float64) {
+	n, k := m.NumStates(), m.NumSymbols()
+	a = make([]float64, n*n)
+	b = make([]float64, n*k)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			a[i*n+j] = m.A.Get(i, j)
+		}
+		for s := 0; s < k; s++ {
+			b[i*k+s] = m.B.Get(i, s)
+		}
+	}
+	return a, b
+}
+
+// Forward returns the scaled forward probabilities of obs as a T×N matrix,
+// along with the scaling factors. Row t is the distribution of the hidden
+// state at time t given obs[0..t], and scales[t] is the probability of
+// obs[t] given obs[0..t-1], so the log-likelihood of obs is the sum of their
+// logs. It returns ErrImpossible if obs has zero probability.
+func (m *Model) Forward(obs []int) (*linalg.Matrix, []float64, error) {
+	if err := m.checkObservations(obs); err != nil {
+		return nil, nil, err
+	}
+	a, b := m.params()
+	alpha, scales, err := m.forward(obs, a, b)
+	if err != nil {
+		return nil, nil, err
+	}
+	alphaMatrix, err := linalg.NewMatrixFromData(alpha, []int{len(obs), m.NumStates()})
+	return alphaMatrix, scales, err
+}
+
+// forward computes the scaled forward probabilities as a flat T×N slice
+func (m *Model) forward(obs []int, a, b []float64) ([]float64, []float64, error) {
+	n, k := m.NumStates(), m.NumSymbols()
+	T := len(obs)
+	alpha := make([]float64, T*n)
+	scales := make([]float64, T)
+
+	for t := 0; t < T; t++ {
+		row := alpha[t*n : (t+1)*n]
+		for j := 0; j < n; j++ {
+			p := 0.0
+			if t == 0 {
+				p = m.Pi[j]
+			} else {
+				prev := alpha[(t-1)*n : t*n]
+				for i, pi := range prev {
+					p += pi * a[i*n+j]
+				}
+			}
+			row[j] = p * b[j*k+obs[t]]
+			scales[t] += row[j]
+		}
+		if scales[t] == 0 {
+			return nil, nil, fmt.Errorf("%w: observation %d cannot be emitted", ErrImpossible, t)
+		}
+		for j := range row {
+			row[j] /= scales[t]
+		}
+	}
+	return alpha, scales, nil
+}
+
+// Backward returns the backward probabilities of obs as a T×N matrix, scaled
+// by the factors returned by Forward so that the elementwise product of the
+// forward and backward rows at time t is the posterior at time t.
+func (m *Model) Backward(obs []int, scales []float64) (*linalg.Matrix, error) {
+	if err := m.checkObservations(obs); err != nil {
+		return nil, err
+	}
+	if len(scales) != len(obs) {
+		return nil, fmt.Errorf(
+			"%d scaling factors given for %d observations", len(scales), len(obs))
+	}
+	a, b := m.params()
+	beta := m.backward(obs, scales, a, b)
+	return linalg.NewMatrixFromData(beta, []int{len(obs), m.NumStates()})
+}
+
+// backward computes the scaled backward probabilities as a flat T×N slice
+func (m *Model) backward(obs []int, scales, a, b []float64) []float64 {
+	n, k := m.NumStates(), m.NumSymbols()
+	T := len(obs)
+	beta := make([]float64, T*n)
+	for i := 0; i < n; i++ {
+		beta[(T-1)*n+i] = 1
+	}
+	// emitted[j] = B[j][o_{t+1}]·β_{t+1}(j) / c_{t+1}
+	emitted := make([]float64, n)
+	for t := T - 2; t >= 0; t-- {
+		next := beta[(t+1)*n : (t+2)*n]
+		for j := 0; j < n; j++ {
+			emitted[j] = b[j*k+obs[t+1]] * next[j] / scales[t+1]
+		}
+		row := beta[t*n : (t+1)*n]
+		for i := 0; i < n; i++ {
+			sum := 0.0
+			for j := 0; j < n; j++ {
+				sum += a[i*n+j] * emitted[j]
+			}
+			row[i] = sum
+		}
+	}
+	return beta
+}
+
+// LogLikelihood returns the natural log of the probability of obs, or
+// -Inf if it is impossible.
+func (m *Model) LogLikelihood(obs []int) (float64, error) {
+	_, scales, err := m.Forward(obs)
+	if errors.Is(err, ErrImpossible) {
+		return math.Inf(-1), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return logSum(scales), nil
+}
+
+// logSum returns the sum of the logs of scales
+func logSum(scales []float64) float64 {
+	sum := 0.0
+	for _, c := range scales {
+		sum += math.Log(c)
+	}
+	return sum
+}
+
+// Posterior returns the posterior marginals of obs as a T×N matrix: entry
+// (t, i) is the probability that the hidden state at time t is i given the
+// whole of obs.
+func (m *Model) Posterior(obs []int) (*linalg.Matrix, error) {
+	if err := m.checkObservations(obs); err != nil {
+		return nil, err
+	}
+	a, b := m.params()
+	alpha, scales, err := m.forward(obs, a, b)
+	if err != nil {
+		return nil, err
+	}
+	beta := m.backward(obs, scales, a, b)
+	for i := range alpha {
+		alpha[i] *= beta[i]
+	}
+	return linalg.NewMatrixFromData(alpha, []int{len(obs), m.NumStates()})
+}
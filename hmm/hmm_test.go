@@ -0,0 +1,209 @@
+package hmm
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/pforderique/markov_chain/linalg"
+)
+
+func newTestModel(t *testing.T, a, b [][]float64, pi []float64) *Model {
+	t.Helper()
+	A, err := linalg.FromRows(a)
+	if err != nil {
+		t.Fatalf("Failed to create transition matrix: %v", err)
+	}
+	data := []float64{}
+	for _, row := range b {
+		data = append(data, row...)
+	}
+	B, err := linalg.NewMatrixFromData(data, []int{len(b), len(b[0])})
+	if err != nil {
+		t.Fatalf("Failed to create emission matrix: %v", err)
+	}
+	m, err := New(A, B, pi)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+	return m
+}
+
+// weatherModel is the classic example with hidden weather (rainy, sunny)
+// and observed activities (walk, shop, clean)
+func weatherModel(t *testing.T) *Model {
+	return newTestModel(t,
+		[][]float64{{0.7, 0.3}, {0.4, 0.6}},
+		[][]float64{{0.1, 0.4, 0.5}, {0.6, 0.3, 0.1}},
+		[]float64{0.6, 0.4})
+}
+
+// bruteForce enumerates every hidden path of m for obs, returning the
+// probability of obs, the posterior marginals and the most likely path
+func bruteForce(m *Model, obs []int) (float64, [][]float64, []int, float64) {
+	n, T := m.NumStates(), len(obs)
+	total := 0.0
+	posterior := make([][]float64, T)
+	for t := range posterior {
+		posterior[t] = make([]float64, n)
+	}
+	var best []int
+	bestP := -1.0
+
+	path := make([]int, T)
+	var walk func(t int, p float64)
+	walk = func(t int, p float64) {
+		if t == T {
+			total += p
+			for s, i := range path {
+				posterior[s][i] += p
+			}
+			if p > bestP {
+				bestP = p
+				best = append([]int(nil), path...)
+			}
+			return
+		}
+		for i := 0; i < n; i++ {
+			path[t] = i
+			q := m.Pi[i]
+			if t > 0 {
+				q = m.A.Get(path[t-1], i)
+			}
+			walk(t+1, p*q*m.B.Get(i, obs[t]))
+		}
+	}
+	walk(0, 1)
+
+	for t := range posterior {
+		for i := range posterior[t] {
+			posterior[t][i] /= total
+		}
+	}
+	return total, posterior, best, bestP
+}
+
+// TestNew calls New with valid and invalid parameters
+func TestNew(t *testing.T) {
+	t.Parallel()
+	A, _ := linalg.FromRows([][]float64{{0.5, 0.5}, {0.2, 0.8}})
+	B, _ := linalg.NewMatrixFromData([]float64{0.5, 0.5, 1, 0}, []int{2, 2})
+	wide, _ := linalg.NewMatrixFromData([]float64{1, 0, 1, 0, 1, 0}, []int{3, 2})
+	badRow, _ := linalg.NewMatrixFromData([]float64{0.5, 0.6, 1, 0}, []int{2, 2})
+
+	tests := []struct {
+		name    string
+		B       *linalg.Matrix
+		pi      []float64
+		wantErr bool
+	}{
+		{"Valid", B, []float64{0.5, 0.5}, false},
+		{"EmissionShape", wide, []float64{0.5, 0.5}, true},
+		{"EmissionRow", badRow, []float64{0.5, 0.5}, true},
+		{"InitialLength", B, []float64{1}, true},
+		{"InitialSum", B, []float64{0.5, 0.4}, true},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			_, err := New(A, test.B, test.pi)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Expected error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+
+	var mismatch *linalg.ErrDimensionMismatch
+	if _, err := New(A, wide, []float64{0.5, 0.5}); !errors.As(err, &mismatch) {
+		t.Fatalf("Expected *ErrDimensionMismatch, got %v", err)
+	}
+}
+
+// TestForwardBackward compares the likelihood and posterior marginals with
+// brute-force enumeration over all hidden paths
+func TestForwardBackward(t *testing.T) {
+	t.Parallel()
+	m := weatherModel(t)
+
+	tests := []struct {
+		name string
+		obs  []int
+	}{
+		{"Single", []int{2}},
+		{"Short", []int{0, 1, 2}},
+		{"Long", []int{0, 0, 2, 1, 2, 0, 1, 1}},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			total, want, _, _ := bruteForce(m, test.obs)
+
+			ll, err := m.LogLikelihood(test.obs)
+			if err != nil {
+				t.Fatalf("LogLikelihood failed: %v", err)
+			}
+			if math.Abs(ll-math.Log(total)) > 1e-12 {
+				t.Fatalf("Expected log-likelihood %f, got %f", math.Log(total), ll)
+			}
+
+			posterior, err := m.Posterior(test.obs)
+			if err != nil {
+				t.Fatalf("Posterior failed: %v", err)
+			}
+			for s := range test.obs {
+				for i := 0; i < 2; i++ {
+					if math.Abs(posterior.Get(s, i)-want[s][i]) > 1e-12 {
+						t.Fatalf("Posterior[%d][%d] = %f, expected %f",
+							s, i, posterior.Get(s, i), want[s][i])
+					}
+				}
+			}
+
+			alpha, scales, err := m.Forward(test.obs)
+			if err != nil {
+				t.Fatalf("Forward failed: %v", err)
+			}
+			beta, err := m.Backward(test.obs, scales)
+			if err != nil {
+				t.Fatalf("Backward failed: %v", err)
+			}
+			last := len(test.obs) - 1
+			if beta.Get(last, 0) != 1 || beta.Get(last, 1) != 1 {
+				t.Fatalf("Expected final backward row of ones")
+			}
+			if got := alpha.Get(0, 0) * beta.Get(0, 0); math.Abs(got-want[0][0]) > 1e-12 {
+				t.Fatalf("α·β at time 0 = %f, expected %f", got, want[0][0])
+			}
+		})
+	}
+}
+
+// TestForwardErrors checks invalid and impossible observation sequences
+func TestForwardErrors(t *testing.T) {
+	t.Parallel()
+	// State 0 only emits symbol 0 and never leaves
+	m := newTestModel(t,
+		[][]float64{{1, 0}, {0.5, 0.5}},
+		[][]float64{{1, 0}, {0.5, 0.5}},
+		[]float64{1, 0})
+
+	if _, _, err := m.Forward([]int{0, 1}); !errors.Is(err, ErrImpossible) {
+		t.Fatalf("Expected ErrImpossible, got %v", err)
+	}
+	if ll, err := m.LogLikelihood([]int{0, 1}); err != nil || !math.IsInf(ll, -1) {
+		t.Fatalf("Expected log-likelihood -Inf, got %v and %v", ll, err)
+	}
+	if _, _, err := m.Forward(nil); err == nil {
+		t.Fatalf("Expected error for empty sequence")
+	}
+	if _, err := m.Posterior([]int{0, 2}); err == nil {
+		t.Fatalf("Expected error for out-of-range symbol")
+	}
+	if _, err := m.Backward([]int{0, 0}, []float64{1}); err == nil {
+		t.Fatalf("Expected error for wrong number of scaling factors")
+	}
+}
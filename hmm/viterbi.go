@@ -0,0 +1,64 @@
+package hmm
+
+import (
+	"fmt"
+	"math"
+)
+
+// Viterbi returns the most likely sequence of hidden states to have emitted
+// obs, along with the natural log of its joint probability with obs. It
+// works in log space, so long sequences do not underflow, and returns
+// ErrImpossible if obs has zero probability.
+func (m *Model) Viterbi(obs []int) ([]int, float64, error) {
+	if err := m.checkObservations(obs); err != nil {
+		return nil, 0, err
+	}
+	n, k := m.NumStates(), m.NumSymbols()
+	T := len(obs)
+	a, b := m.params()
+	for i := range a {
+		a[i] = math.Log(a[i])
+	}
+	for i := range b {
+		b[i] = math.Log(b[i])
+	}
+
+	// delta holds the best log probability of a path ending in each state;
+	// back[t*n+j] is the predecessor of state j on the best path at time t
+	delta := make([]float64, n)
+	next := make([]float64, n)
+	back := make([]int, T*n)
+	for j := 0; j < n; j++ {
+		delta[j] = math.Log(m.Pi[j]) + b[j*k+obs[0]]
+	}
+	for t := 1; t < T; t++ {
+		for j := 0; j < n; j++ {
+			best, arg := math.Inf(-1), 0
+			for i := 0; i < n; i++ {
+				if p := delta[i] + a[i*n+j]; p > best {
+					best, arg = p, i
+				}
+			}
+			next[j] = best + b[j*k+obs[t]]
+			back[t*n+j] = arg
+		}
+		delta, next = next, delta
+	}
+
+	last := 0
+	for j := 1; j < n; j++ {
+		if delta[j] > delta[last] {
+			last = j
+		}
+	}
+	if math.IsInf(delta[last], -1) {
+		return nil, 0, fmt.Errorf("%w: no path of hidden states emits it", ErrImpossible)
+	}
+
+	path := make([]int, T)
+	path[T-1] = last
+	for t := T - 1; t > 0; t-- {
+		path[t-1] = back[t*n+path[t]]
+	}
+	return path, delta[last], nil
+}
@@ -0,0 +1,79 @@
+package hmm
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// TestViterbi compares the decoded path with brute-force enumeration
+func TestViterbi(t *testing.T) {
+	t.Parallel()
+	m := weatherModel(t)
+
+	tests := []struct {
+		name string
+		obs  []int
+	}{
+		{"Single", []int{0}},
+		{"Short", []int{0, 1, 2}},
+		{"Long", []int{2, 2, 0, 1, 0, 0, 2, 1, 2}},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			_, _, want, wantP := bruteForce(m, test.obs)
+			path, logP, err := m.Viterbi(test.obs)
+			if err != nil {
+				t.Fatalf("Viterbi failed: %v", err)
+			}
+			if !reflect.DeepEqual(path, want) {
+				t.Fatalf("Expected path %v, got %v", want, path)
+			}
+			if math.Abs(logP-math.Log(wantP)) > 1e-12 {
+				t.Fatalf("Expected log probability %f, got %f", math.Log(wantP), logP)
+			}
+		})
+	}
+}
+
+// TestViterbiLong decodes a sequence long enough to underflow without logs
+func TestViterbiLong(t *testing.T) {
+	t.Parallel()
+	// Sticky states that each mostly emit their own symbol
+	m := newTestModel(t,
+		[][]float64{{0.95, 0.05}, {0.05, 0.95}},
+		[][]float64{{0.9, 0.1}, {0.1, 0.9}},
+		[]float64{0.5, 0.5})
+
+	obs := make([]int, 10000)
+	for i := 5000; i < 10000; i++ {
+		obs[i] = 1
+	}
+	obs[2500] = 1 // a single outlier is explained as noise
+
+	path, logP, err := m.Viterbi(obs)
+	if err != nil {
+		t.Fatalf("Viterbi failed: %v", err)
+	}
+	// e^-745 is below the smallest float64
+	if math.IsInf(logP, 0) || logP > -745 {
+		t.Fatalf("Expected finite log probability below -745, got %f", logP)
+	}
+	for i, s := range path {
+		if want := i / 5000; s != want {
+			t.Fatalf("Expected state %d at time %d, got %d", want, i, s)
+		}
+	}
+
+	impossible := newTestModel(t,
+		[][]float64{{1, 0}, {0, 1}},
+		[][]float64{{1, 0}, {1, 0}},
+		[]float64{0.5, 0.5})
+	if _, _, err := impossible.Viterbi([]int{0, 1}); !errors.Is(err, ErrImpossible) {
+		t.Fatalf("Expected ErrImpossible, got %v", err)
+	}
+}
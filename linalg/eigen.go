@@ -0,0 +1,648 @@
+package linalg
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"slices"
+)
+
+// ErrNoConvergence is returned when an iterative method gives up before
+// reaching its tolerance.
+type ErrNoConvergence struct {
+	Method     string
+	Iterations int
+}
+
+func (e *ErrNoConvergence) Error() string {
+	return fmt.Sprintf("%s did not converge after %d iterations", e.Method, e.Iterations)
+}
+
+// maxQRIterations bounds the shifted QR iterations spent on one eigenvalue
+const maxQRIterations = 100
+
+// EigenDecomposition holds the eigenvalues of a SquareMatrix A, sorted by
+// decreasing modulus, and optionally the matching right eigenvectors, with
+// A·Vectors[j] = Values[j]·Vectors[j] and each vector of unit length.
+type EigenDecomposition struct {
+	Values  []complex128
+	Vectors [][]complex128
+}
+
+// Eigen computes the eigenvalues of A by reducing it to upper Hessenberg form
+// and running the shifted double QR iteration, following EISPACK's orthes and
+// hqr2. If vectors is true the eigenvectors are computed too.
+func (A *SquareMatrix) Eigen(vectors bool) (*EigenDecomposition, error) {
+	n := A.n
+	H := make([][]float64, n)
+	V := make([][]float64, n)
+	for i := 0; i < n; i++ {
+		H[i] = make([]float64, n)
+		copy(H[i], A.data[i*n:(i+1)*n])
+		V[i] = make([]float64, n)
+	}
+	d := make([]float64, n)
+	e := make([]float64, n)
+
+	orthes(H, V)
+	if err := hqr2(H, V, d, e, vectors); err != nil {
+		return nil, err
+	}
+
+	result := &EigenDecomposition{Values: make([]complex128, n)}
+	for j := 0; j < n; j++ {
+		result.Values[j] = complex(d[j], e[j])
+	}
+	if vectors {
+		result.Vectors = make([][]complex128, n)
+		for j := 0; j < n; j++ {
+			v := make([]complex128, n)
+			for i := 0; i < n; i++ {
+				switch {
+				case e[j] > 0:
+					v[i] = complex(V[i][j], V[i][j+1])
+				case e[j] < 0:
+					v[i] = complex(V[i][j-1], -V[i][j])
+				default:
+					v[i] = complex(V[i][j], 0)
+				}
+			}
+			result.Vectors[j] = normalizeComplex(v)
+		}
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(cmplx.Abs(result.Values[b]), cmplx.Abs(result.Values[a]))
+	})
+	values := make([]complex128, n)
+	for i, j := range order {
+		values[i] = result.Values[j]
+	}
+	result.Values = values
+	if vectors {
+		vecs := make([][]complex128, n)
+		for i, j := range order {
+			vecs[i] = result.Vectors[j]
+		}
+		result.Vectors = vecs
+	}
+	return result, nil
+}
+
+// DominantEigen finds the eigenvalue of A with the largest modulus and its
+// unit-length eigenvector by power iteration. It returns an
+// *ErrNoConvergence if that eigenvalue is not real and strictly dominant.
+func (A *SquareMatrix) DominantEigen() (float64, []float64, error) {
+	const maxIterations = 10000
+	const tolerance = 1e-12
+
+	n := A.n
+	v := make([]float64, n)
+	for i := range v {
+		// Start off any eigenvector likely to be orthogonal to the answer
+		v[i] = 1 + float64(i)/float64(n)
+	}
+	normalizeReal(v)
+
+	lambda := 0.0
+	for iter := 1; iter <= maxIterations; iter++ {
+		w := make([]float64, n)
+		for i := 0; i < n; i++ {
+			row := A.data[i*n : (i+1)*n]
+			for j, a := range row {
+				w[i] += a * v[j]
+			}
+		}
+		// Rayleigh quotient vᵀAv of the unit vector v
+		next := 0.0
+		for i := range w {
+			next += v[i] * w[i]
+		}
+		if normalizeReal(w) == 0 {
+			return 0, v, nil
+		}
+		// Keep the sign of the vector stable when lambda is negative
+		if next < 0 {
+			for i := range w {
+				w[i] = -w[i]
+			}
+		}
+
+		diff := 0.0
+		for i := range w {
+			diff = math.Max(diff, math.Abs(w[i]-v[i]))
+		}
+		v, lambda = w, next
+		if diff < tolerance {
+			return lambda, v, nil
+		}
+	}
+	return lambda, v, &ErrNoConvergence{"power iteration", maxIterations}
+}
+
+// SecondLargestEigenvalueModulus returns the second largest modulus among the
+// eigenvalues of A, counted with multiplicity. For a transition matrix, one
+// minus this is the spectral gap that governs the mixing time.
+func (A *SquareMatrix) SecondLargestEigenvalueModulus() (float64, error) {
+	if A.n < 2 {
+		return 0, nil
+	}
+	eig, err := A.Eigen(false)
+	if err != nil {
+		return 0, err
+	}
+	return cmplx.Abs(eig.Values[1]), nil
+}
+
+// normalizeReal scales v to unit length and returns its original length
+func normalizeReal(v []float64) float64 {
+	norm := 0.0
+	for _, x := range v {
+		norm += x * x
+	}
+	norm = math.Sqrt(norm)
+	if norm > 0 {
+		for i := range v {
+			v[i] /= norm
+		}
+	}
+	return norm
+}
+
+// normalizeComplex scales v to unit length
+func normalizeComplex(v []complex128) []complex128 {
+	norm := 0.0
+	for _, x := range v {
+		norm += real(x)*real(x) + imag(x)*imag(x)
+	}
+	norm = math.Sqrt(norm)
+	if norm > 0 {
+		for i := range v {
+			v[i] /= complex(norm, 0)
+		}
+	}
+	return v
+}
+
+// orthes reduces H to upper Hessenberg form with Householder similarity
+// transformations, accumulating them into V
+func orthes(H, V [][]float64) {
+	n := len(H)
+	low, high := 0, n-1
+	ort := make([]float64, n)
+
+	for m := low + 1; m <= high-1; m++ {
+		scale := 0.0
+		for i := m; i <= high; i++ {
+			scale += math.Abs(H[i][m-1])
+		}
+		if scale == 0 {
+			continue
+		}
+
+		h := 0.0
+		for i := high; i >= m; i-- {
+			ort[i] = H[i][m-1] / scale
+			h += ort[i] * ort[i]
+		}
+		g := math.Sqrt(h)
+		if ort[m] > 0 {
+			g = -g
+		}
+		h -= ort[m] * g
+		ort[m] -= g
+
+		// H = (I - uuᵀ/h) H (I - uuᵀ/h)
+		for j := m; j < n; j++ {
+			f := 0.0
+			for i := high; i >= m; i-- {
+				f += ort[i] * H[i][j]
+			}
+			f /= h
+			for i := m; i <= high; i++ {
+				H[i][j] -= f * ort[i]
+			}
+		}
+		for i := 0; i <= high; i++ {
+			f := 0.0
+			for j := high; j >= m; j-- {
+				f += ort[j] * H[i][j]
+			}
+			f /= h
+			for j := m; j <= high; j++ {
+				H[i][j] -= f * ort[j]
+			}
+		}
+		ort[m] *= scale
+		H[m][m-1] = scale * g
+	}
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			V[i][j] = 0
+		}
+		V[i][i] = 1
+	}
+	for m := high - 1; m >= low+1; m-- {
+		if H[m][m-1] == 0 {
+			continue
+		}
+		for i := m + 1; i <= high; i++ {
+			ort[i] = H[i][m-1]
+		}
+		for j := m; j <= high; j++ {
+			g := 0.0
+			for i := m; i <= high; i++ {
+				g += ort[i] * V[i][j]
+			}
+			// Double division avoids possible underflow
+			g = (g / ort[m]) / H[m][m-1]
+			for i := m; i <= high; i++ {
+				V[i][j] += g * ort[i]
+			}
+		}
+	}
+}
+
+// hqr2 reduces the Hessenberg matrix H to real Schur form with the shifted
+// double QR iteration, storing the real and imaginary parts of the
+// eigenvalues in d and e. If vectors is true it then back substitutes for the
+// eigenvectors and leaves them in V, with a complex pair (e[j] > 0,
+// e[j+1] < 0) stored as real part in column j and imaginary part in j+1.
+func hqr2(H, V [][]float64, d, e []float64, vectors bool) error {
+	nn := len(H)
+	n := nn - 1
+	low, high := 0, nn-1
+	eps := math.Pow(2, -52)
+	exshift := 0.0
+	var p, q, r, s, z, t, w, x, y float64
+
+	norm := 0.0
+	for i := 0; i < nn; i++ {
+		for j := max(i-1, 0); j < nn; j++ {
+			norm += math.Abs(H[i][j])
+		}
+	}
+
+	iter := 0
+	for n >= low {
+		// Look for a single small sub-diagonal element
+		l := n
+		for l > low {
+			s = math.Abs(H[l-1][l-1]) + math.Abs(H[l][l])
+			if s == 0 {
+				s = norm
+			}
+			if math.Abs(H[l][l-1]) < eps*s {
+				break
+			}
+			l--
+		}
+
+		if l == n {
+			// One root found
+			H[n][n] += exshift
+			d[n] = H[n][n]
+			e[n] = 0
+			n--
+			iter = 0
+		} else if l == n-1 {
+			// Two roots found
+			w = H[n][n-1] * H[n-1][n]
+			p = (H[n-1][n-1] - H[n][n]) / 2
+			q = p*p + w
+			z = math.Sqrt(math.Abs(q))
+			H[n][n] += exshift
+			H[n-1][n-1] += exshift
+			x = H[n][n]
+
+			if q >= 0 {
+				// Real pair
+				if p >= 0 {
+					z = p + z
+				} else {
+					z = p - z
+				}
+				d[n-1] = x + z
+				d[n] = d[n-1]
+				if z != 0 {
+					d[n] = x - w/z
+				}
+				e[n-1] = 0
+				e[n] = 0
+				x = H[n][n-1]
+				s = math.Abs(x) + math.Abs(z)
+				p = x / s
+				q = z / s
+				r = math.Sqrt(p*p + q*q)
+				p /= r
+				q /= r
+
+				for j := n - 1; j < nn; j++ {
+					z = H[n-1][j]
+					H[n-1][j] = q*z + p*H[n][j]
+					H[n][j] = q*H[n][j] - p*z
+				}
+				for i := 0; i <= n; i++ {
+					z = H[i][n-1]
+					H[i][n-1] = q*z + p*H[i][n]
+					H[i][n] = q*H[i][n] - p*z
+				}
+				for i := low; i <= high; i++ {
+					z = V[i][n-1]
+					V[i][n-1] = q*z + p*V[i][n]
+					V[i][n] = q*V[i][n] - p*z
+				}
+			} else {
+				// Complex pair
+				d[n-1] = x + p
+				d[n] = x + p
+				e[n-1] = z
+				e[n] = -z
+			}
+			n -= 2
+			iter = 0
+		} else {
+			// No convergence yet; form the shift
+			x = H[n][n]
+			y = 0
+			w = 0
+			if l < n {
+				y = H[n-1][n-1]
+				w = H[n][n-1] * H[n-1][n]
+			}
+
+			// Wilkinson's original ad hoc shift
+			if iter == 10 {
+				exshift += x
+				for i := low; i <= n; i++ {
+					H[i][i] -= x
+				}
+				s = math.Abs(H[n][n-1]) + math.Abs(H[n-1][n-2])
+				x = 0.75 * s
+				y = x
+				w = -0.4375 * s * s
+			}
+
+			// MATLAB's ad hoc shift
+			if iter == 30 {
+				s = (y - x) / 2
+				s = s*s + w
+				if s > 0 {
+					s = math.Sqrt(s)
+					if y < x {
+						s = -s
+					}
+					s = x - w/((y-x)/2+s)
+					for i := low; i <= n; i++ {
+						H[i][i] -= s
+					}
+					exshift += s
+					x, y, w = 0.964, 0.964, 0.964
+				}
+			}
+
+			iter++
+			if iter > maxQRIterations {
+				return &ErrNoConvergence{"QR iteration", iter}
+			}
+
+			// Look for two consecutive small sub-diagonal elements
+			m := n - 2
+			for m >= l {
+				z = H[m][m]
+				r = x - z
+				s = y - z
+				p = (r*s-w)/H[m+1][m] + H[m][m+1]
+				q = H[m+1][m+1] - z - r - s
+				r = H[m+2][m+1]
+				s = math.Abs(p) + math.Abs(q) + math.Abs(r)
+				p /= s
+				q /= s
+				r /= s
+				if m == l {
+					break
+				}
+				if math.Abs(H[m][m-1])*(math.Abs(q)+math.Abs(r)) <
+					eps*(math.Abs(p)*(math.Abs(H[m-1][m-1])+math.Abs(z)+math.Abs(H[m+1][m+1]))) {
+					break
+				}
+				m--
+			}
+
+			for i := m + 2; i <= n; i++ {
+				H[i][i-2] = 0
+				if i > m+2 {
+					H[i][i-3] = 0
+				}
+			}
+
+			// Double QR step on rows l..n and columns m..n
+			for k := m; k <= n-1; k++ {
+				notlast := k != n-1
+				if k != m {
+					p = H[k][k-1]
+					q = H[k+1][k-1]
+					r = 0
+					if notlast {
+						r = H[k+2][k-1]
+					}
+					x = math.Abs(p) + math.Abs(q) + math.Abs(r)
+					if x == 0 {
+						continue
+					}
+					p /= x
+					q /= x
+					r /= x
+				}
+
+				s = math.Sqrt(p*p + q*q + r*r)
+				if p < 0 {
+					s = -s
+				}
+				if s == 0 {
+					continue
+				}
+				if k != m {
+					H[k][k-1] = -s * x
+				} else if l != m {
+					H[k][k-1] = -H[k][k-1]
+				}
+				p += s
+				x = p / s
+				y = q / s
+				z = r / s
+				q /= p
+				r /= p
+
+				for j := k; j < nn; j++ {
+					p = H[k][j] + q*H[k+1][j]
+					if notlast {
+						p += r * H[k+2][j]
+						H[k+2][j] -= p * z
+					}
+					H[k][j] -= p * x
+					H[k+1][j] -= p * y
+				}
+				for i := 0; i <= min(n, k+3); i++ {
+					p = x*H[i][k] + y*H[i][k+1]
+					if notlast {
+						p += z * H[i][k+2]
+						H[i][k+2] -= p * r
+					}
+					H[i][k] -= p
+					H[i][k+1] -= p * q
+				}
+				for i := low; i <= high; i++ {
+					p = x*V[i][k] + y*V[i][k+1]
+					if notlast {
+						p += z * V[i][k+2]
+						V[i][k+2] -= p * r
+					}
+					V[i][k] -= p
+					V[i][k+1] -= p * q
+				}
+			}
+		}
+	}
+
+	if !vectors || norm == 0 {
+		return nil
+	}
+
+	// Back substitute to find the vectors of the upper triangular form
+	for n = nn - 1; n >= 0; n-- {
+		p = d[n]
+		q = e[n]
+
+		if q == 0 {
+			// Real vector
+			l := n
+			H[n][n] = 1
+			for i := n - 1; i >= 0; i-- {
+				w = H[i][i] - p
+				r = 0
+				for j := l; j <= n; j++ {
+					r += H[i][j] * H[j][n]
+				}
+				if e[i] < 0 {
+					z = w
+					s = r
+					continue
+				}
+				l = i
+				if e[i] == 0 {
+					if w != 0 {
+						H[i][n] = -r / w
+					} else {
+						H[i][n] = -r / (eps * norm)
+					}
+				} else {
+					// Solve real equations
+					x = H[i][i+1]
+					y = H[i+1][i]
+					q = (d[i]-p)*(d[i]-p) + e[i]*e[i]
+					t = (x*s - z*r) / q
+					H[i][n] = t
+					if math.Abs(x) > math.Abs(z) {
+						H[i+1][n] = (-r - w*t) / x
+					} else {
+						H[i+1][n] = (-s - y*t) / z
+					}
+				}
+
+				// Overflow control
+				t = math.Abs(H[i][n])
+				if (eps*t)*t > 1 {
+					for j := i; j <= n; j++ {
+						H[j][n] /= t
+					}
+				}
+			}
+		} else if q < 0 {
+			// Complex vector
+			l := n - 1
+
+			// Last vector component imaginary so matrix is triangular
+			if math.Abs(H[n][n-1]) > math.Abs(H[n-1][n]) {
+				H[n-1][n-1] = q / H[n][n-1]
+				H[n-1][n] = -(H[n][n] - p) / H[n][n-1]
+			} else {
+				c := complex(0, -H[n-1][n]) / complex(H[n-1][n-1]-p, q)
+				H[n-1][n-1] = real(c)
+				H[n-1][n] = imag(c)
+			}
+			H[n][n-1] = 0
+			H[n][n] = 1
+
+			for i := n - 2; i >= 0; i-- {
+				var ra, sa float64
+				for j := l; j <= n; j++ {
+					ra += H[i][j] * H[j][n-1]
+					sa += H[i][j] * H[j][n]
+				}
+				w = H[i][i] - p
+
+				if e[i] < 0 {
+					z = w
+					r = ra
+					s = sa
+					continue
+				}
+				l = i
+				if e[i] == 0 {
+					c := complex(-ra, -sa) / complex(w, q)
+					H[i][n-1] = real(c)
+					H[i][n] = imag(c)
+				} else {
+					// Solve complex equations
+					x = H[i][i+1]
+					y = H[i+1][i]
+					vr := (d[i]-p)*(d[i]-p) + e[i]*e[i] - q*q
+					vi := (d[i] - p) * 2 * q
+					if vr == 0 && vi == 0 {
+						vr = eps * norm * (math.Abs(w) + math.Abs(q) +
+							math.Abs(x) + math.Abs(y) + math.Abs(z))
+					}
+					c := complex(x*r-z*ra+q*sa, x*s-z*sa-q*ra) / complex(vr, vi)
+					H[i][n-1] = real(c)
+					H[i][n] = imag(c)
+					if math.Abs(x) > math.Abs(z)+math.Abs(q) {
+						H[i+1][n-1] = (-ra - w*H[i][n-1] + q*H[i][n]) / x
+						H[i+1][n] = (-sa - w*H[i][n] - q*H[i][n-1]) / x
+					} else {
+						c := complex(-r-y*H[i][n-1], -s-y*H[i][n]) / complex(z, q)
+						H[i+1][n-1] = real(c)
+						H[i+1][n] = imag(c)
+					}
+				}
+
+				// Overflow control
+				t = math.Max(math.Abs(H[i][n-1]), math.Abs(H[i][n]))
+				if (eps*t)*t > 1 {
+					for j := i; j <= n; j++ {
+						H[j][n-1] /= t
+						H[j][n] /= t
+					}
+				}
+			}
+		}
+	}
+
+	// Back transform to get the eigenvectors of the original matrix
+	for j := nn - 1; j >= low; j-- {
+		for i := low; i <= high; i++ {
+			z = 0
+			for k := low; k <= min(j, high); k++ {
+				z += V[i][k] * H[k][j]
+			}
+			V[i][j] = z
+		}
+	}
+	return nil
+}
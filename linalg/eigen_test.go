@@ -0,0 +1,132 @@
+package linalg
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// TestEigenValues calls SquareMatrix.Eigen on matrices with known spectra
+func TestEigenValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		A    *SquareMatrix
+		want []complex128
+	}{
+		{"1x1", &SquareMatrix{[]float64{-3}, 1}, []complex128{-3}},
+		{"Diagonal", Diag(1, -4, 2), []complex128{-4, 2, 1}},
+		{"Symmetric", &SquareMatrix{[]float64{2, 1, 1, 2}, 2}, []complex128{3, 1}},
+		{"Rotation", &SquareMatrix{[]float64{0, -1, 1, 0}, 2}, []complex128{1i, -1i}},
+		{
+			"Stochastic",
+			&SquareMatrix{[]float64{0.9, 0.1, 0.5, 0.5}, 2},
+			[]complex128{1, 0.4},
+		},
+		{
+			"Cycle",
+			&SquareMatrix{[]float64{0, 1, 0, 0, 0, 1, 1, 0, 0}, 3},
+			[]complex128{
+				1,
+				complex(-0.5, math.Sqrt(3)/2),
+				complex(-0.5, -math.Sqrt(3)/2),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			eig, err := test.A.Eigen(false)
+			if err != nil {
+				t.Fatalf(`Eigen failed: %v`, err)
+			}
+			if len(eig.Values) != len(test.want) {
+				t.Fatalf(`Expected %d eigenvalues, got %d`, len(test.want), len(eig.Values))
+			}
+			// Eigenvalues of equal modulus may come back in either order
+			for _, want := range test.want {
+				found := false
+				for _, value := range eig.Values {
+					if cmplx.Abs(value-want) < 1e-12 {
+						found = true
+					}
+				}
+				if !found {
+					t.Fatalf(`Expected eigenvalue %v in %v`, want, eig.Values)
+				}
+			}
+			for i := 1; i < len(eig.Values); i++ {
+				if cmplx.Abs(eig.Values[i]) > cmplx.Abs(eig.Values[i-1])+1e-12 {
+					t.Fatalf(`Eigenvalues not sorted by modulus: %v`, eig.Values)
+				}
+			}
+		})
+	}
+}
+
+// TestEigenVectors checks that Av = λv for every computed eigenpair
+func TestEigenVectors(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []int{2, 5, 12, 30} {
+		A := createSquareMatrix(random, n)
+		eig, err := A.Eigen(true)
+		if err != nil {
+			t.Fatalf(`n=%d: Eigen failed: %v`, n, err)
+		}
+		for j, lambda := range eig.Values {
+			v := eig.Vectors[j]
+			for i := 0; i < n; i++ {
+				var Av complex128
+				for k := 0; k < n; k++ {
+					Av += complex(A.Get(i, k), 0) * v[k]
+				}
+				if cmplx.Abs(Av-lambda*v[i]) > 1e-9 {
+					t.Fatalf(`n=%d: (Av)[%d] = %v, expected λv = %v`,
+						n, i, Av, lambda*v[i])
+				}
+			}
+		}
+	}
+}
+
+// TestDominantEigen calls SquareMatrix.DominantEigen and
+// SquareMatrix.SecondLargestEigenvalueModulus on a transition matrix
+func TestDominantEigen(t *testing.T) {
+	t.Parallel()
+	P := &SquareMatrix{[]float64{
+		0.5, 0.25, 0.25,
+		0.5, 0, 0.5,
+		0.25, 0.25, 0.5,
+	}, 3}
+
+	lambda, v, err := P.DominantEigen()
+	if err != nil {
+		t.Fatalf(`DominantEigen failed: %v`, err)
+	}
+	if math.Abs(lambda-1) > 1e-9 {
+		t.Fatalf(`Expected dominant eigenvalue 1, got %f`, lambda)
+	}
+	// Rows sum to 1 so the right eigenvector is constant
+	for i := range v {
+		if math.Abs(v[i]-1/math.Sqrt(3)) > 1e-9 {
+			t.Fatalf(`Expected constant eigenvector, got %v`, v)
+		}
+	}
+
+	slem, err := P.SecondLargestEigenvalueModulus()
+	if err != nil {
+		t.Fatalf(`SecondLargestEigenvalueModulus failed: %v`, err)
+	}
+	if math.Abs(slem-0.25) > 1e-12 {
+		t.Fatalf(`Expected second largest modulus 0.25, got %f`, slem)
+	}
+
+	rotation := &SquareMatrix{[]float64{0, -1, 1, 0}, 2}
+	if _, _, err := rotation.DominantEigen(); err == nil {
+		t.Fatalf(`Expected power iteration to fail on a rotation`)
+	}
+}
@@ -0,0 +1,40 @@
+package linalg
+
+import "fmt"
+
+// ErrDimensionMismatch is returned when the shapes of two operands are not
+// compatible for an operation.
+type ErrDimensionMismatch struct {
+	Op string
+	A  []int
+	B  []int
+}
+
+func (e *ErrDimensionMismatch) Error() string {
+	return fmt.Sprintf("%s: incompatible dimensions %v and %v", e.Op, e.A, e.B)
+}
+
+// ErrIndexOutOfRange is returned when coordinates do not address an element
+// of a matrix with dimensions Dims.
+type ErrIndexOutOfRange struct {
+	Index []int
+	Dims  []int
+}
+
+func (e *ErrIndexOutOfRange) Error() string {
+	return fmt.Sprintf("index %v out of range for dimensions %v", e.Index, e.Dims)
+}
+
+// checkIndex returns an *ErrIndexOutOfRange if coors does not address an
+// element of a matrix with dimensions dims
+func checkIndex(coors, dims []int) error {
+	valid := len(coors) == len(dims)
+	for i := 0; valid && i < len(dims); i++ {
+		valid = coors[i] >= 0 && coors[i] < dims[i]
+	}
+	if !valid {
+		return &ErrIndexOutOfRange{
+			append([]int(nil), coors...), append([]int(nil), dims...)}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package linalg
+
+import (
+	"fmt"
+	"math"
+)
+
+// Pow returns A^k using O(log k) multiplications by repeated squaring. A
+// negative k raises the inverse of A, which fails with an *ErrSingular if A
+// is singular.
+func (A *SquareMatrix) Pow(k int) (*SquareMatrix, error) {
+	base := A
+	if k < 0 {
+		inv, err := A.Inverse()
+		if err != nil {
+			return nil, err
+		}
+		base, k = inv, -k
+	}
+
+	result, _ := Identity(A.n)
+	for k > 0 {
+		if k%2 == 1 {
+			result = result.Multiply(base)
+		}
+		k /= 2
+		if k > 0 {
+			base = base.Multiply(base)
+		}
+	}
+	return result, nil
+}
+
+// pade13 holds the coefficients of the [13/13] Padé approximant to e^x
+var pade13 = [14]float64{
+	64764752532480000, 32382376266240000, 7771770303897600,
+	1187353796428800, 129060195264000, 10559470521600, 670442572800,
+	33522128640, 1323241920, 40840800, 960960, 16380, 182, 1,
+}
+
+// theta13 is the largest 1-norm for which the [13/13] Padé approximant is
+// accurate to double precision (Higham, 2005)
+const theta13 = 5.371920351148152
+
+// Exp returns the matrix exponential e^A, computed by scaling A until its
+// 1-norm is below theta13, applying the [13/13] Padé approximant and squaring
+// the result back up. It fails if A has a NaN or infinite entry.
+func (A *SquareMatrix) Exp() (*SquareMatrix, error) {
+	n := A.n
+	s := 0
+	norm := A.norm1()
+	if math.IsNaN(norm) || math.IsInf(norm, 0) {
+		return nil, fmt.Errorf("cannot exponentiate a matrix with 1-norm %v", norm)
+	}
+	if norm > theta13 {
+		s = int(math.Ceil(math.Log2(norm / theta13)))
+	}
+	X := A.scale(math.Pow(2, -float64(s)))
+
+	I, _ := Identity(n)
+	X2 := X.Multiply(X)
+	X4 := X2.Multiply(X2)
+	X6 := X4.Multiply(X2)
+	b := pade13
+
+	U := X6.Multiply(linearCombination(
+		[]float64{b[13], b[11], b[9]}, X6, X4, X2))
+	U = X.Multiply(U.Add(linearCombination(
+		[]float64{b[7], b[5], b[3], b[1]}, X6, X4, X2, I)))
+	V := X6.Multiply(linearCombination(
+		[]float64{b[12], b[10], b[8]}, X6, X4, X2))
+	V = V.Add(linearCombination(
+		[]float64{b[6], b[4], b[2], b[0]}, X6, X4, X2, I))
+
+	// R = (V - U)^-1 (V + U)
+	d := linearCombination([]float64{1, -1}, V, U).LU()
+	if err := d.checkSingular(); err != nil {
+		return nil, fmt.Errorf("Padé denominator: %w", err)
+	}
+	numerator := V.Add(U)
+	R := &SquareMatrix{make([]float64, n*n), n}
+	column := make([]float64, n)
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			column[i] = numerator.data[i*n+j]
+		}
+		for i, value := range d.solve(column) {
+			R.data[i*n+j] = value
+		}
+	}
+
+	for ; s > 0; s-- {
+		R = R.Multiply(R)
+	}
+	return R, nil
+}
+
+// norm1 returns the maximum absolute column sum of A
+func (A *SquareMatrix) norm1() float64 {
+	norm := 0.0
+	for j := 0; j < A.n; j++ {
+		sum := 0.0
+		for i := 0; i < A.n; i++ {
+			sum += math.Abs(A.data[i*A.n+j])
+		}
+		norm = math.Max(norm, sum)
+	}
+	return norm
+}
+
+// scale returns cA
+func (A *SquareMatrix) scale(c float64) *SquareMatrix {
+	C := SquareMatrix{make([]float64, A.Size()), A.n}
+	for i, a := range A.data {
+		C.data[i] = c * a
+	}
+	return &C
+}
+
+// linearCombination returns Σ coefficients[i]·matrices[i]
+func linearCombination(coefficients []float64, matrices ...*SquareMatrix) *SquareMatrix {
+	C := SquareMatrix{make([]float64, matrices[0].Size()), matrices[0].n}
+	for k, M := range matrices {
+		c := coefficients[k]
+		for i, m := range M.data {
+			C.data[i] += c * m
+		}
+	}
+	return &C
+}
@@ -0,0 +1,127 @@
+package linalg
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func squareMatricesClose(A, B *SquareMatrix, tol float64) bool {
+	if A.n != B.n {
+		return false
+	}
+	for i := range A.data {
+		scale := math.Max(1, math.Abs(B.data[i]))
+		if math.Abs(A.data[i]-B.data[i]) > tol*scale {
+			return false
+		}
+	}
+	return true
+}
+
+// TestExp calls SquareMatrix.Exp on matrices with closed-form exponentials
+func TestExp(t *testing.T) {
+	t.Parallel()
+	c, s := math.Cos(2), math.Sin(2)
+
+	tests := []struct {
+		name string
+		A    *SquareMatrix
+		want *SquareMatrix
+	}{
+		{"Zero", createSquareMatrix(zero, 3), createSquareMatrix(identity, 3)},
+		{"Diagonal", Diag(1, -2, 0.5), Diag(math.E, math.Exp(-2), math.Exp(0.5))},
+		{"LargeNorm", Diag(20, -20), Diag(math.Exp(20), math.Exp(-20))},
+		{
+			"Nilpotent",
+			&SquareMatrix{[]float64{0, 1, 0, 0}, 2},
+			&SquareMatrix{[]float64{1, 1, 0, 1}, 2},
+		},
+		{
+			"Rotation",
+			&SquareMatrix{[]float64{0, 2, -2, 0}, 2},
+			&SquareMatrix{[]float64{c, s, -s, c}, 2},
+		},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			result, err := test.A.Exp()
+			if err != nil {
+				t.Fatalf(`Exp failed: %v`, err)
+			}
+			if !squareMatricesClose(result, test.want, 1e-12) {
+				t.Fatalf(`Expected\n%s, got\n%s`, test.want, result)
+			}
+		})
+	}
+}
+
+// TestExpNonFinite checks that SquareMatrix.Exp rejects NaN and infinite
+// entries
+func TestExpNonFinite(t *testing.T) {
+	t.Parallel()
+	for _, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		A := Diag(1, value)
+		if _, err := A.Exp(); err == nil {
+			t.Fatalf(`Expected error for entry %v`, value)
+		}
+	}
+}
+
+// TestExpGenerator checks that e^{Qt} is stochastic for a generator Q
+func TestExpGenerator(t *testing.T) {
+	t.Parallel()
+	Q := &SquareMatrix{[]float64{-3, 2, 1, 1, -1, 0, 4, 4, -8}, 3}
+	P, err := Q.scale(7.5).Exp()
+	if err != nil {
+		t.Fatalf(`Exp failed: %v`, err)
+	}
+	for i := 0; i < 3; i++ {
+		sum := 0.0
+		for j := 0; j < 3; j++ {
+			if P.Get(i, j) < 0 {
+				t.Fatalf(`P[%d][%d] = %g is negative`, i, j, P.Get(i, j))
+			}
+			sum += P.Get(i, j)
+		}
+		if math.Abs(sum-1) > 1e-12 {
+			t.Fatalf(`Row %d sums to %f`, i, sum)
+		}
+	}
+}
+
+// TestPow compares SquareMatrix.Pow against repeated multiplication
+func TestPow(t *testing.T) {
+	t.Parallel()
+	A := &SquareMatrix{[]float64{0.9, 0.1, 0.5, 0.5}, 2}
+
+	want := createSquareMatrix(identity, 2)
+	for k := 0; k <= 13; k++ {
+		result, err := A.Pow(k)
+		if err != nil {
+			t.Fatalf(`Pow(%d) failed: %v`, k, err)
+		}
+		if !squareMatricesClose(result, want, 1e-12) {
+			t.Fatalf(`Pow(%d): expected\n%s, got\n%s`, k, want, result)
+		}
+		want = want.Multiply(A)
+	}
+
+	B := &SquareMatrix{[]float64{2, 1, 1, 1}, 2}
+	inverseCubed, err := B.Pow(-3)
+	if err != nil {
+		t.Fatalf(`Pow(-3) failed: %v`, err)
+	}
+	cubed, _ := B.Pow(3)
+	if !squareMatricesClose(cubed.Multiply(inverseCubed), createSquareMatrix(identity, 2), 1e-12) {
+		t.Fatalf(`Expected B^3 B^-3 = I`)
+	}
+
+	var singular *ErrSingular
+	if _, err := createSquareMatrix(ones, 2).Pow(-1); !errors.As(err, &singular) {
+		t.Fatalf(`Expected ErrSingular, got %v`, err)
+	}
+}
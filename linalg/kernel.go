@@ -0,0 +1,55 @@
+package linalg
+
+// multiplyAddTile accumulates c += ab for an m x kk tile a and a kk x nn tile
+// b into the m x nn tile c. Each slice starts at the top left of its tile, and
+// lda, ldb and ldc are the row strides of the matrices the tiles live in.
+//
+// Rows of c are updated from four rows of b at a time so that four entries of
+// a stay in registers, and every inner slice is cut to len(cRow) so that the
+// compiler can drop the bounds checks in the innermost loop.
+func multiplyAddTile(a, b, c []float64, lda, ldb, ldc, m, kk, nn int) {
+	for i := 0; i < m; i++ {
+		cRow := c[i*ldc : i*ldc+nn]
+		aRow := a[i*lda : i*lda+kk]
+
+		k := 0
+		for ; k+4 <= kk; k += 4 {
+			a0, a1, a2, a3 := aRow[k], aRow[k+1], aRow[k+2], aRow[k+3]
+			b0 := b[k*ldb : k*ldb+nn]
+			b1 := b[(k+1)*ldb : (k+1)*ldb+nn]
+			b2 := b[(k+2)*ldb : (k+2)*ldb+nn]
+			b3 := b[(k+3)*ldb : (k+3)*ldb+nn]
+			b0, b1, b2, b3 = b0[:len(cRow)], b1[:len(cRow)], b2[:len(cRow)], b3[:len(cRow)]
+			for j := range cRow {
+				cRow[j] += a0*b0[j] + a1*b1[j] + a2*b2[j] + a3*b3[j]
+			}
+		}
+		for ; k < kk; k++ {
+			aik := aRow[k]
+			bRow := b[k*ldb : k*ldb+nn]
+			bRow = bRow[:len(cRow)]
+			for j := range cRow {
+				cRow[j] += aik * bRow[j]
+			}
+		}
+	}
+}
+
+// tiledMultiplyAdd accumulates c += ab for n x n row-major a, b and c, working
+// through tile x tile pieces so that the pieces in use stay in cache
+func tiledMultiplyAdd(a, b, c []float64, n, tile int) {
+	if tile <= 0 {
+		tile = n
+	}
+	for i0 := 0; i0 < n; i0 += tile {
+		m := min(tile, n-i0)
+		for j0 := 0; j0 < n; j0 += tile {
+			nn := min(tile, n-j0)
+			for k0 := 0; k0 < n; k0 += tile {
+				kk := min(tile, n-k0)
+				multiplyAddTile(
+					a[i0*n+k0:], b[k0*n+j0:], c[i0*n+j0:], n, n, n, m, kk, nn)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package linalg
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// naiveMultiply is the textbook triple loop used as a reference for kernels
+func naiveMultiply(a, b []float64, n int) []float64 {
+	c := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			var value float64 = 0
+			for k := 0; k < n; k++ {
+				value += a[i*n+k] * b[k*n+j]
+			}
+			c[i*n+j] = value
+		}
+	}
+	return c
+}
+
+// TestTiledMultiplyAdd compares tiledMultiplyAdd against naiveMultiply for
+// sizes and tiles that leave ragged edges and remainder columns
+func TestTiledMultiplyAdd(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		n    int
+		tile int
+	}{
+		{"1x1", 1, 8},
+		{"SingleTile", 7, 8},
+		{"Even", 32, 8},
+		{"Ragged", 37, 8},
+		{"OddTile", 50, 13},
+		{"NoTile", 21, 0},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			a := createSquareMatrix(random, test.n).data
+			b := createSquareMatrix(random, test.n).data
+			c := make([]float64, test.n*test.n)
+			for i := range c {
+				c[i] = rand.Float64()
+			}
+			want := naiveMultiply(a, b, test.n)
+			for i := range want {
+				want[i] += c[i]
+			}
+
+			tiledMultiplyAdd(a, b, c, test.n, test.tile)
+			for i := range want {
+				if math.Abs(want[i]-c[i]) > 1e-9 {
+					t.Fatalf(`Element %d differs. Expected %f, got %f`,
+						i, want[i], c[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package linalg
+
+import (
+	"fmt"
+	"math"
+)
+
+// ErrSingular is returned when a SquareMatrix has no inverse. Pivot is the
+// column at which elimination found no usable pivot.
+type ErrSingular struct {
+	Pivot int
+}
+
+func (e *ErrSingular) Error() string {
+	return fmt.Sprintf("matrix is singular: zero pivot in column %d", e.Pivot)
+}
+
+// LUDecomposition is the factorization PA = LU of a SquareMatrix A, where L
+// is unit lower triangular, U is upper triangular and P is a permutation.
+type LUDecomposition struct {
+	L *SquareMatrix
+	U *SquareMatrix
+	// Perm describes P: row i of PA is row Perm[i] of A.
+	Perm []int
+	// sign is the determinant of P, +1 or -1
+	sign float64
+	// tol is the magnitude below which a pivot counts as zero
+	tol float64
+}
+
+// LU factors A with Gaussian elimination and partial pivoting. It succeeds
+// for singular matrices too; Solve and Inverse report them.
+func (A *SquareMatrix) LU() *LUDecomposition {
+	n := A.n
+	lu := make([]float64, len(A.data))
+	copy(lu, A.data)
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	sign := 1.0
+
+	maxAbs := 0.0
+	for _, v := range A.data {
+		maxAbs = math.Max(maxAbs, math.Abs(v))
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(lu[row*n+col]) > math.Abs(lu[pivot*n+col]) {
+				pivot = row
+			}
+		}
+		if pivot != col {
+			for k := 0; k < n; k++ {
+				lu[col*n+k], lu[pivot*n+k] = lu[pivot*n+k], lu[col*n+k]
+			}
+			perm[col], perm[pivot] = perm[pivot], perm[col]
+			sign = -sign
+		}
+
+		diag := lu[col*n+col]
+		if diag == 0 {
+			continue
+		}
+		pivotRow := lu[col*n+col+1 : (col+1)*n]
+		for row := col + 1; row < n; row++ {
+			factor := lu[row*n+col] / diag
+			lu[row*n+col] = factor
+			if factor == 0 {
+				continue
+			}
+			rest := lu[row*n+col+1 : (row+1)*n]
+			for k := range rest {
+				rest[k] -= factor * pivotRow[k]
+			}
+		}
+	}
+
+	L := &SquareMatrix{make([]float64, n*n), n}
+	U := &SquareMatrix{make([]float64, n*n), n}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			switch {
+			case j < i:
+				L.data[i*n+j] = lu[i*n+j]
+			case j == i:
+				L.data[i*n+j] = 1
+				U.data[i*n+j] = lu[i*n+j]
+			default:
+				U.data[i*n+j] = lu[i*n+j]
+			}
+		}
+	}
+
+	return &LUDecomposition{
+		L:    L,
+		U:    U,
+		Perm: perm,
+		sign: sign,
+		tol:  float64(n) * maxAbs * 1e-15,
+	}
+}
+
+// Det returns the determinant of the factored matrix.
+func (d *LUDecomposition) Det() float64 {
+	det := d.sign
+	for i := 0; i < d.U.n; i++ {
+		det *= d.U.data[i*d.U.n+i]
+	}
+	return det
+}
+
+// checkSingular returns an *ErrSingular for the first pivot of U that is
+// effectively zero
+func (d *LUDecomposition) checkSingular() error {
+	for i := 0; i < d.U.n; i++ {
+		if math.Abs(d.U.data[i*d.U.n+i]) <= d.tol {
+			return &ErrSingular{i}
+		}
+	}
+	return nil
+}
+
+// Solve returns x with Ax = b for the factored matrix A.
+func (d *LUDecomposition) Solve(b []float64) ([]float64, error) {
+	n := d.U.n
+	if len(b) != n {
+		return nil, &ErrDimensionMismatch{"Solve", []int{n, n}, []int{len(b)}}
+	}
+	if err := d.checkSingular(); err != nil {
+		return nil, err
+	}
+	return d.solve(b), nil
+}
+
+// solve performs forward and back substitution without checking for
+// singularity
+func (d *LUDecomposition) solve(b []float64) []float64 {
+	n := d.U.n
+	x := make([]float64, n)
+	for i := 0; i < n; i++ {
+		x[i] = b[d.Perm[i]]
+	}
+
+	// Ly = Pb
+	for i := 0; i < n; i++ {
+		row := d.L.data[i*n : i*n+i]
+		for k, l := range row {
+			x[i] -= l * x[k]
+		}
+	}
+	// Ux = y
+	for i := n - 1; i >= 0; i-- {
+		row := d.U.data[i*n : (i+1)*n]
+		for k := i + 1; k < n; k++ {
+			x[i] -= row[k] * x[k]
+		}
+		x[i] /= row[i]
+	}
+	return x
+}
+
+// Det returns the determinant of A.
+func (A *SquareMatrix) Det() float64 {
+	return A.LU().Det()
+}
+
+// Solve returns x with Ax = b, or an *ErrSingular if A is singular.
+func (A *SquareMatrix) Solve(b []float64) ([]float64, error) {
+	return A.LU().Solve(b)
+}
+
+// Inverse returns the inverse of A, or an *ErrSingular if A is singular.
+func (A *SquareMatrix) Inverse() (*SquareMatrix, error) {
+	d := A.LU()
+	if err := d.checkSingular(); err != nil {
+		return nil, err
+	}
+
+	n := A.n
+	inv := &SquareMatrix{make([]float64, n*n), n}
+	e := make([]float64, n)
+	for j := 0; j < n; j++ {
+		e[j] = 1
+		column := d.solve(e)
+		e[j] = 0
+		for i, v := range column {
+			inv.data[i*n+j] = v
+		}
+	}
+	return inv, nil
+}
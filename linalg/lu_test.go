@@ -0,0 +1,135 @@
+package linalg
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// TestLU checks that PA = LU with L unit lower and U upper triangular
+func TestLU(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		A    *SquareMatrix
+	}{
+		{"NeedsPivot", &SquareMatrix{[]float64{0, 1, 1, 0}, 2}},
+		{"3x3", &SquareMatrix{[]float64{2, 1, 1, 4, -6, 0, -2, 7, 2}, 3}},
+		{"Random", createSquareMatrix(random, 30)},
+		{"Singular", &SquareMatrix{[]float64{1, 2, 2, 4}, 2}},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			n := test.A.n
+			d := test.A.LU()
+			LU := SquareMatrixMultiplySimple(d.L, d.U)
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					if j > i && d.L.Get(i, j) != 0 || j < i && d.U.Get(i, j) != 0 {
+						t.Fatalf(`L or U not triangular at (%d, %d)`, i, j)
+					}
+					want := test.A.Get(d.Perm[i], j)
+					if math.Abs(LU.Get(i, j)-want) > 1e-12 {
+						t.Fatalf(`(LU)[%d][%d] = %f, expected %f`,
+							i, j, LU.Get(i, j), want)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestDet calls SquareMatrix.Det
+func TestDet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		A    *SquareMatrix
+		want float64
+	}{
+		{"Identity", createSquareMatrix(identity, 4), 1},
+		{"Swap", &SquareMatrix{[]float64{0, 1, 1, 0}, 2}, -1},
+		{"3x3", &SquareMatrix{[]float64{2, 1, 1, 4, -6, 0, -2, 7, 2}, 3}, -16},
+		{"Singular", &SquareMatrix{[]float64{1, 2, 2, 4}, 2}, 0},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			if det := test.A.Det(); math.Abs(det-test.want) > 1e-12 {
+				t.Fatalf(`Expected determinant %f, got %f`, test.want, det)
+			}
+		})
+	}
+}
+
+// TestInverseAndSolve calls SquareMatrix.Inverse and SquareMatrix.Solve
+func TestInverseAndSolve(t *testing.T) {
+	t.Parallel()
+	A := createSquareMatrix(random, 25)
+	for i := 0; i < 25; i++ {
+		// Make A diagonally dominant so it is well conditioned
+		A.data[i*25+i] += 25
+	}
+
+	inv, err := A.Inverse()
+	if err != nil {
+		t.Fatalf(`Inverse failed: %v`, err)
+	}
+	product := SquareMatrixMultiplySimple(A, inv)
+	for i := 0; i < 25; i++ {
+		for j := 0; j < 25; j++ {
+			want := 0.0
+			if i == j {
+				want = 1
+			}
+			if math.Abs(product.Get(i, j)-want) > 1e-12 {
+				t.Fatalf(`(A*A^-1)[%d][%d] = %g, expected %g`,
+					i, j, product.Get(i, j), want)
+			}
+		}
+	}
+
+	b := make([]float64, 25)
+	for i := range b {
+		b[i] = float64(i)
+	}
+	x, err := A.Solve(b)
+	if err != nil {
+		t.Fatalf(`Solve failed: %v`, err)
+	}
+	for i := 0; i < 25; i++ {
+		Ax := 0.0
+		for j := 0; j < 25; j++ {
+			Ax += A.Get(i, j) * x[j]
+		}
+		if math.Abs(Ax-b[i]) > 1e-12 {
+			t.Fatalf(`(Ax)[%d] = %g, expected %g`, i, Ax, b[i])
+		}
+	}
+}
+
+// TestSingular checks that Inverse and Solve report singular matrices
+func TestSingular(t *testing.T) {
+	t.Parallel()
+	A := &SquareMatrix{[]float64{1, 2, 3, 2, 4, 6, 1, 0, 1}, 3}
+
+	var singular *ErrSingular
+	if _, err := A.Inverse(); !errors.As(err, &singular) {
+		t.Fatalf(`Expected ErrSingular, got %v`, err)
+	}
+	if _, err := A.Solve([]float64{1, 2, 3}); !errors.As(err, &singular) {
+		t.Fatalf(`Expected ErrSingular, got %v`, err)
+	}
+
+	var dimErr *ErrDimensionMismatch
+	if _, err := A.Solve([]float64{1, 2}); !errors.As(err, &dimErr) {
+		t.Fatalf(`Expected ErrDimensionMismatch, got %v`, err)
+	}
+}
@@ -1,6 +1,7 @@
 package linalg
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -11,6 +12,50 @@ type Matrix struct {
 	dims []int
 }
 
+// NewMatrix returns a zero-filled Matrix with the given dimensions.
+func NewMatrix(dims ...int) (*Matrix, error) {
+	size, err := sizeOf(dims)
+	if err != nil {
+		return nil, err
+	}
+	return &Matrix{make([]float64, size), append([]int(nil), dims...)}, nil
+}
+
+// NewMatrixFromData returns a Matrix with the given dimensions backed by data,
+// which is laid out in row-major order.
+func NewMatrixFromData(data []float64, dims []int) (*Matrix, error) {
+	size, err := sizeOf(dims)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != size {
+		return nil, fmt.Errorf(
+			"data of length %d cannot fill a Matrix with dimensions %v",
+			len(data), dims)
+	}
+	return &Matrix{data, append([]int(nil), dims...)}, nil
+}
+
+// sizeOf returns the number of elements in a Matrix with dimensions dims
+func sizeOf(dims []int) (int, error) {
+	if len(dims) == 0 {
+		return 0, fmt.Errorf("Matrix must have at least one dimension")
+	}
+	size := 1
+	for i, dim := range dims {
+		if dim <= 0 {
+			return 0, fmt.Errorf("dimension %d has invalid length %d", i, dim)
+		}
+		size *= dim
+	}
+	return size, nil
+}
+
+// Dims returns a copy of the dimensions of A.
+func (A Matrix) Dims() []int {
+	return append([]int(nil), A.dims...)
+}
+
 func (A Matrix) Size() int {
 	size := 1
 	for _, dim := range A.dims {
@@ -45,6 +90,14 @@ func (A Matrix) Get(coors ...int) float64 {
     return A.data[index]
 }
 
+// TryGet is like Get but returns an *ErrIndexOutOfRange instead of panicking.
+func (A Matrix) TryGet(coors ...int) (float64, error) {
+	if err := checkIndex(coors, A.dims); err != nil {
+		return 0, err
+	}
+	return A.Get(coors...), nil
+}
+
 func (A *Matrix) Set(coors []int, value float64) {
     if len(coors) != len(A.dims) {
         panic(fmt.Sprintf(
@@ -62,6 +115,15 @@ func (A *Matrix) Set(coors []int, value float64) {
     A.data[index] = value
 }
 
+// TrySet is like Set but returns an *ErrIndexOutOfRange instead of panicking.
+func (A *Matrix) TrySet(coors []int, value float64) error {
+	if err := checkIndex(coors, A.dims); err != nil {
+		return err
+	}
+	A.Set(coors, value)
+	return nil
+}
+
 func (A Matrix) String() string {
 	// s := "["
 	// // data_idx
@@ -109,6 +171,15 @@ func (A *Matrix) Add(B *Matrix) *Matrix {
 	return A
 }
 
+// TryAdd is like Add but returns an *ErrDimensionMismatch instead of
+// panicking.
+func (A *Matrix) TryAdd(B *Matrix) (*Matrix, error) {
+	if !reflect.DeepEqual(A.dims, B.dims) {
+		return nil, &ErrDimensionMismatch{"Add", A.Dims(), B.Dims()}
+	}
+	return A.Add(B), nil
+}
+
 func (A *Matrix) Multiply(B *Matrix) *Matrix {
 	if len(A.dims) != len(B.dims) || len(B.dims) != 2 {
 		panic("Both matrices must be 2D")
@@ -117,25 +188,92 @@ func (A *Matrix) Multiply(B *Matrix) *Matrix {
 		panic(fmt.Sprintf(
 			"Matrix dimensions %v x %v cannot be multiplied", A.dims, B.dims))
 	}
+
+	workers := CurrentTuning().Workers
+	if workers > 1 && A.dims[0] > 1 &&
+		A.dims[0]*A.dims[1]*B.dims[1] >= parallelMultiplyThreshold {
+		return matrixMultiplyParallel(A, B, min(workers, A.dims[0]))
+	}
 	return matrixMultiplySimple(A, B)
 }
 
+// TryMultiply is like Multiply but returns an *ErrDimensionMismatch instead of
+// panicking.
+func (A *Matrix) TryMultiply(B *Matrix) (*Matrix, error) {
+	if len(A.dims) != 2 || len(B.dims) != 2 || A.dims[1] != B.dims[0] {
+		return nil, &ErrDimensionMismatch{"Multiply", A.Dims(), B.Dims()}
+	}
+	return A.Multiply(B), nil
+}
+
+// matrixMultiplySimple computes C = AB on the calling goroutine
 func matrixMultiplySimple(A *Matrix, B *Matrix) *Matrix {
 	I, J, K := A.dims[0], A.dims[1], B.dims[1]
+	C := Matrix{make([]float64, I*K), []int{I, K}}
+	multiplyRows(A.data, B.data, C.data, 0, I, J, K)
+	return &C
+}
+
+// MultiplyContext is like Multiply but computes C = AB in bands of rows on the
+// DefaultPool, stopping between bands and returning ctx.Err() once ctx is
+// done. If progress is non-nil it is called as each band is finished.
+func (A *Matrix) MultiplyContext(
+	ctx context.Context, B *Matrix, progress ProgressFunc,
+) (*Matrix, error) {
+	if len(A.dims) != 2 || len(B.dims) != 2 || A.dims[1] != B.dims[0] {
+		return nil, &ErrDimensionMismatch{"Multiply", A.Dims(), B.Dims()}
+	}
 
+	I, J, K := A.dims[0], A.dims[1], B.dims[1]
 	C := Matrix{make([]float64, I*K), []int{I, K}}
-	for i:=0; i<I; i++ {
-		for k:=0; k<K; k++ {
-			var value float64 = 0;
-			for j:=0; j<J; j++ {
-				value += A.Get(i, j) * B.Get(j, k)
-			}
-			C.Set([]int{i, k}, value)
-		}
+
+	rowsPerBand := max(CurrentTuning().TileSize, 1)
+	bands := (I + rowsPerBand - 1) / rowsPerBand
+	counter := &progressCounter{total: bands, report: progress}
+	err := DefaultPool().RunContext(ctx, bands, func(band int) {
+		start := band * rowsPerBand
+		end := min(start+rowsPerBand, I)
+		multiplyRows(A.data, B.data, C.data, start, end, J, K)
+		counter.step()
+	})
+	if err != nil {
+		return nil, err
 	}
+	return &C, nil
+}
+
+// parallelMultiplyThreshold is the number of scalar multiply-adds (I*J*K)
+// below which goroutine overhead outweighs the gain from multiplying in
+// parallel
+const parallelMultiplyThreshold = 1 << 18
+
+// matrixMultiplyParallel splits the rows of C = AB into one band per worker
+// and computes the bands on the DefaultPool
+func matrixMultiplyParallel(A *Matrix, B *Matrix, workers int) *Matrix {
+	I, J, K := A.dims[0], A.dims[1], B.dims[1]
+	C := Matrix{make([]float64, I*K), []int{I, K}}
+
+	rowsPerWorker := (I + workers - 1) / workers
+	bands := (I + rowsPerWorker - 1) / rowsPerWorker
+	DefaultPool().Run(bands, func(band int) {
+		start := band * rowsPerWorker
+		end := min(start+rowsPerWorker, I)
+		multiplyRows(A.data, B.data, C.data, start, end, J, K)
+	})
 	return &C
 }
 
-// func matrixMultiplyParallel(A *Matrix, B *Matrix) *Matrix {
-// 	// TODO: Research and implement me.
-// }
+// multiplyRows accumulates rows [start, end) of c = ab, where b is J x K and
+// all three are in row-major order. The i-j-k loop order walks b and c
+// contiguously instead of striding down the columns of b.
+func multiplyRows(a, b, c []float64, start, end, J, K int) {
+	for i := start; i < end; i++ {
+		cRow := c[i*K : (i+1)*K]
+		for j, aij := range a[i*J : (i+1)*J] {
+			bRow := b[j*K : (j+1)*K]
+			for k, bjk := range bRow {
+				cRow[k] += aij * bjk
+			}
+		}
+	}
+}
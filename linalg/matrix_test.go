@@ -1,6 +1,9 @@
 package linalg
 
 import (
+	"context"
+	"errors"
+	"math"
 	"math/rand"
 	"reflect"
 	"testing"
@@ -191,6 +194,145 @@ func TestMultiply(t *testing.T) {
 	}
 }
 
+// TestNewMatrixFromData calls NewMatrix and NewMatrixFromData
+func TestNewMatrixFromData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct{
+		name string
+		data []float64
+		dims []int
+		wantErr bool
+	}{
+		{"1D", []float64{1, 2, 3}, []int{3}, false},
+		{"3D", []float64{1, 2, 3, 4, 5, 6, 7, 8}, []int{2, 2, 2}, false},
+		{"TooShort", []float64{1, 2, 3}, []int{2, 2}, true},
+		{"NoDims", []float64{}, []int{}, true},
+		{"ZeroDim", []float64{}, []int{2, 0}, true},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			A, err := NewMatrixFromData(test.data, test.dims)
+			if (err != nil) != test.wantErr {
+				t.Fatalf(`Expected error %v, got %v`, test.wantErr, err)
+			}
+			if err == nil && !reflect.DeepEqual(A.Dims(), test.dims) {
+				t.Fatalf(`Expected dimensions %v, got %v`, test.dims, A.Dims())
+			}
+		})
+	}
+
+	B, err := NewMatrix(2, 3, 4)
+	if err != nil {
+		t.Fatalf(`NewMatrix failed: %v`, err)
+	}
+	if len(B.data) != 24 {
+		t.Fatalf(`Expected 24 elements, got %d`, len(B.data))
+	}
+	if _, err := NewMatrix(2, -1); err == nil {
+		t.Fatalf(`Expected error for negative dimension`)
+	}
+}
+
+// TestTryErrors checks that the Try methods return typed errors
+func TestTryErrors(t *testing.T) {
+	t.Parallel()
+	A := &Matrix{[]float64{1, 2, 3, 4, 5, 6}, []int{2, 3}}
+	B := &Matrix{[]float64{1, 2, 3, 4}, []int{2, 2}}
+
+	var dimErr *ErrDimensionMismatch
+	if _, err := A.TryMultiply(B); !errors.As(err, &dimErr) {
+		t.Fatalf(`Expected ErrDimensionMismatch, got %v`, err)
+	}
+	if !reflect.DeepEqual(dimErr.A, []int{2, 3}) ||
+		!reflect.DeepEqual(dimErr.B, []int{2, 2}) {
+		t.Fatalf(`Expected shapes [2 3] and [2 2], got %v and %v`,
+			dimErr.A, dimErr.B)
+	}
+	if _, err := A.TryAdd(B); !errors.As(err, &dimErr) {
+		t.Fatalf(`Expected ErrDimensionMismatch, got %v`, err)
+	}
+
+	var indexErr *ErrIndexOutOfRange
+	if _, err := A.TryGet(2, 0); !errors.As(err, &indexErr) {
+		t.Fatalf(`Expected ErrIndexOutOfRange, got %v`, err)
+	}
+	if err := A.TrySet([]int{0}, 1); !errors.As(err, &indexErr) {
+		t.Fatalf(`Expected ErrIndexOutOfRange, got %v`, err)
+	}
+
+	C, err := B.TryMultiply(A)
+	if err != nil {
+		t.Fatalf(`TryMultiply failed: %v`, err)
+	}
+	if value, err := C.TryGet(1, 2); err != nil || value != 33 {
+		t.Fatalf(`Expected 33, got %f (%v)`, value, err)
+	}
+}
+
+// TestMultiplyParallel compares matrixMultiplyParallel against
+// matrixMultiplySimple for several worker counts
+func TestMultiplyParallel(t *testing.T) {
+	t.Parallel()
+	A := &Matrix{make([]float64, 70*50), []int{70, 50}}
+	B := &Matrix{make([]float64, 50*90), []int{50, 90}}
+	for i := range A.data {
+		A.data[i] = rand.Float64()
+	}
+	for i := range B.data {
+		B.data[i] = rand.Float64()
+	}
+	want := matrixMultiplySimple(A, B)
+
+	for _, workers := range []int{1, 3, 8, 70} {
+		result := matrixMultiplyParallel(A, B, workers)
+		if !reflect.DeepEqual(result.dims, want.dims) {
+			t.Fatalf(`Expected dimensions %v, got %v`, want.dims, result.dims)
+		}
+		for i := range want.data {
+			if math.Abs(want.data[i]-result.data[i]) > 1e-9 {
+				t.Fatalf(`%d workers: element %d differs. Expected %f, got %f`,
+					workers, i, want.data[i], result.data[i])
+			}
+		}
+	}
+}
+
+// TestMultiplyContext calls Matrix.MultiplyContext and checks progress
+// reporting and cancellation
+func TestMultiplyContext(t *testing.T) {
+	t.Parallel()
+	A := &Matrix{[]float64{1, 2, 3, 4, 5, 6}, []int{2, 3}}
+	B := &Matrix{[]float64{7, 10, 13}, []int{3, 1}}
+
+	var lastDone, lastTotal int
+	result, err := A.MultiplyContext(context.Background(), B,
+		func(done, total int) { lastDone, lastTotal = done, total })
+	if err != nil {
+		t.Fatalf(`MultiplyContext failed: %v`, err)
+	}
+	if !reflect.DeepEqual(result.data, []float64{66, 156}) {
+		t.Fatalf(`Expected [66 156], got %v`, result.data)
+	}
+	if lastTotal == 0 || lastDone != lastTotal {
+		t.Fatalf(`Expected progress to finish, got %d/%d`, lastDone, lastTotal)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := A.MultiplyContext(ctx, B, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf(`Expected context.Canceled, got %v`, err)
+	}
+
+	var dimErr *ErrDimensionMismatch
+	if _, err := A.MultiplyContext(context.Background(), A, nil); !errors.As(err, &dimErr) {
+		t.Fatalf(`Expected ErrDimensionMismatch, got %v`, err)
+	}
+}
+
 // ===========================================================================
 func BenchmarkMultiply(b *testing.B) {
 	size := 1024
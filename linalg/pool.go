@@ -0,0 +1,133 @@
+package linalg
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Pool is a set of worker goroutines that the parallel kernels share
+// instead of starting goroutines of their own.
+type Pool struct {
+	workers  atomic.Int64
+	tasks    chan func()
+	done     chan struct{}
+	resizeMu sync.Mutex
+	once     sync.Once
+}
+
+var (
+	defaultPoolOnce sync.Once
+	defaultPool     atomic.Pointer[Pool]
+)
+
+// NewPool starts a Pool with the given number of workers.
+func NewPool(workers int) *Pool {
+	p := &Pool{tasks: make(chan func()), done: make(chan struct{})}
+	p.resize(workers)
+	return p
+}
+
+// resize starts or stops workers until p has the given number of them.
+// Surplus workers stop once they finish their current tasks.
+func (p *Pool) resize(workers int) {
+	workers = max(workers, 1)
+	p.resizeMu.Lock()
+	defer p.resizeMu.Unlock()
+	current := int(p.workers.Load())
+	for i := current; i < workers; i++ {
+		go p.work()
+	}
+	if surplus := current - workers; surplus > 0 {
+		// A nil task tells one worker to stop
+		go func() {
+			for i := 0; i < surplus; i++ {
+				select {
+				case p.tasks <- nil:
+				case <-p.done:
+					return
+				}
+			}
+		}()
+	}
+	p.workers.Store(int64(workers))
+}
+
+// work runs tasks until p is closed or it receives a nil task
+func (p *Pool) work() {
+	for {
+		select {
+		case task := <-p.tasks:
+			if task == nil {
+				return
+			}
+			task()
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// DefaultPool returns the process-wide Pool used by the multiply kernels,
+// sized to CurrentTuning().Workers (GOMAXPROCS unless overridden). SetTuning
+// resizes it.
+func DefaultPool() *Pool {
+	defaultPoolOnce.Do(func() {
+		defaultPool.Store(NewPool(CurrentTuning().Workers))
+	})
+	return defaultPool.Load()
+}
+
+// Workers returns the number of worker goroutines in p.
+func (p *Pool) Workers() int {
+	return int(p.workers.Load())
+}
+
+// Run calls task(i) for every i in [0, n) and returns once all calls have
+// finished. The calling goroutine works through the indices alongside any
+// idle workers, so Run never waits on a busy Pool and may be called from
+// within a task.
+func (p *Pool) Run(n int, task func(i int)) {
+	p.RunContext(context.Background(), n, task)
+}
+
+// RunContext is like Run but stops starting new calls once ctx is done. It
+// returns ctx.Err() if any index was skipped, and in every case only returns
+// after all calls already started have finished.
+func (p *Pool) RunContext(ctx context.Context, n int, task func(i int)) error {
+	var next, completed atomic.Int64
+	work := func() {
+		for ctx.Err() == nil {
+			i := int(next.Add(1) - 1)
+			if i >= n {
+				return
+			}
+			task(i)
+			completed.Add(1)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for h := 0; h < min(p.Workers(), n-1); h++ {
+		wg.Add(1)
+		select {
+		case p.tasks <- func() { defer wg.Done(); work() }:
+		default:
+			// Every worker is busy; the caller will pick up the slack
+			wg.Done()
+		}
+	}
+	work()
+	wg.Wait()
+
+	if int(completed.Load()) < n {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// Close stops the workers once they finish their current tasks. Run still
+// works after Close, but only on the calling goroutine.
+func (p *Pool) Close() {
+	p.once.Do(func() { close(p.done) })
+}
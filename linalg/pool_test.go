@@ -0,0 +1,70 @@
+package linalg
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+// TestPoolRun checks that Pool.Run calls the task once for every index
+func TestPoolRun(t *testing.T) {
+	t.Parallel()
+	pool := NewPool(3)
+	defer pool.Close()
+
+	for _, n := range []int{0, 1, 2, 100} {
+		counts := make([]int32, n)
+		pool.Run(n, func(i int) {
+			atomic.AddInt32(&counts[i], 1)
+		})
+		for i, count := range counts {
+			if count != 1 {
+				t.Fatalf(`n=%d: index %d ran %d times`, n, i, count)
+			}
+		}
+	}
+}
+
+// TestPoolRunNested checks that tasks can call Run on their own Pool without
+// deadlocking when every worker is busy
+func TestPoolRunNested(t *testing.T) {
+	t.Parallel()
+	pool := NewPool(2)
+	defer pool.Close()
+
+	var total atomic.Int64
+	pool.Run(8, func(i int) {
+		pool.Run(8, func(j int) {
+			pool.Run(4, func(k int) {
+				total.Add(1)
+			})
+		})
+	})
+	if total.Load() != 8*8*4 {
+		t.Fatalf(`Expected %d calls, got %d`, 8*8*4, total.Load())
+	}
+}
+
+// TestPoolResize checks that a Pool keeps running every task as it grows and
+// shrinks, and that SetTuning resizes DefaultPool
+func TestPoolResize(t *testing.T) {
+	pool := NewPool(2)
+	defer pool.Close()
+
+	for _, workers := range []int{5, 1, 3} {
+		pool.resize(workers)
+		if pool.Workers() != workers {
+			t.Fatalf(`Expected %d workers, got %d`, workers, pool.Workers())
+		}
+		var total atomic.Int64
+		pool.Run(100, func(i int) { total.Add(1) })
+		if total.Load() != 100 {
+			t.Fatalf(`workers=%d: expected 100 calls, got %d`, workers, total.Load())
+		}
+	}
+
+	defer SetTuning(Tuning{})
+	SetTuning(Tuning{Workers: 3})
+	if got := DefaultPool().Workers(); got != 3 {
+		t.Fatalf(`Expected DefaultPool to have 3 workers after SetTuning, got %d`, got)
+	}
+}
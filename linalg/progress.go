@@ -0,0 +1,27 @@
+package linalg
+
+import "sync"
+
+// ProgressFunc is called by the context-aware multiplies each time a unit of
+// work finishes, with the number of units done so far out of total. Calls are
+// serialized and done increases by one each time.
+type ProgressFunc func(done, total int)
+
+// progressCounter reports completed units of work to a ProgressFunc, which
+// may be nil
+type progressCounter struct {
+	mutex  sync.Mutex
+	done   int
+	total  int
+	report ProgressFunc
+}
+
+func (c *progressCounter) step() {
+	if c.report == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.done++
+	c.report(c.done, c.total)
+}
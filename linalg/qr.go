@@ -0,0 +1,184 @@
+package linalg
+
+import (
+	"fmt"
+	"math"
+)
+
+// QRDecomposition is the factorization AP = QR of an m x n Matrix A computed
+// with Householder reflections and column pivoting. With k = min(m, n), Q is
+// m x k with orthonormal columns, R is k x n upper triangular with diagonal
+// entries of non-increasing magnitude, and P is a column permutation.
+type QRDecomposition struct {
+	Q *Matrix
+	R *Matrix
+	// Perm describes P: column i of AP is column Perm[i] of A.
+	Perm []int
+
+	m, n int
+	// reflectors[j] is the Householder vector applied to rows j..m-1 at step
+	// j, or nil if that column was already zero
+	reflectors [][]float64
+}
+
+// QR factors the 2-D Matrix A.
+func (A *Matrix) QR() (*QRDecomposition, error) {
+	if len(A.dims) != 2 {
+		return nil, fmt.Errorf("QR requires a 2-D Matrix, got dimensions %v", A.dims)
+	}
+	m, n := A.dims[0], A.dims[1]
+	k := min(m, n)
+
+	a := make([]float64, len(A.data))
+	copy(a, A.data)
+	perm := make([]int, n)
+	for j := range perm {
+		perm[j] = j
+	}
+	reflectors := make([][]float64, k)
+
+	for j := 0; j < k; j++ {
+		// Pivot the remaining column with the largest norm into place
+		pivot, pivotNorm := j, -1.0
+		for c := j; c < n; c++ {
+			norm := 0.0
+			for r := j; r < m; r++ {
+				norm += a[r*n+c] * a[r*n+c]
+			}
+			if norm > pivotNorm {
+				pivot, pivotNorm = c, norm
+			}
+		}
+		if pivot != j {
+			for r := 0; r < m; r++ {
+				a[r*n+j], a[r*n+pivot] = a[r*n+pivot], a[r*n+j]
+			}
+			perm[j], perm[pivot] = perm[pivot], perm[j]
+		}
+
+		norm := math.Sqrt(pivotNorm)
+		if norm == 0 {
+			continue
+		}
+		alpha := -norm
+		if a[j*n+j] < 0 {
+			alpha = norm
+		}
+
+		// v = x - alpha*e1, so that (I - 2vvᵀ/vᵀv)x = alpha*e1
+		v := make([]float64, m-j)
+		for r := j; r < m; r++ {
+			v[r-j] = a[r*n+j]
+		}
+		v[0] -= alpha
+		reflectors[j] = v
+
+		applyReflector(v, a, n, j, j+1, n)
+		a[j*n+j] = alpha
+		for r := j + 1; r < m; r++ {
+			a[r*n+j] = 0
+		}
+	}
+
+	R := &Matrix{make([]float64, k*n), []int{k, n}}
+	for i := 0; i < k; i++ {
+		copy(R.data[i*n+i:(i+1)*n], a[i*n+i:(i+1)*n])
+	}
+
+	// Q is the first k columns of H0 H1 ... H(k-1)
+	Q := &Matrix{make([]float64, m*k), []int{m, k}}
+	for i := 0; i < k; i++ {
+		Q.data[i*k+i] = 1
+	}
+	for j := k - 1; j >= 0; j-- {
+		if reflectors[j] != nil {
+			applyReflector(reflectors[j], Q.data, k, j, 0, k)
+		}
+	}
+
+	return &QRDecomposition{Q, R, perm, m, n, reflectors}, nil
+}
+
+// applyReflector applies I - 2vvᵀ/vᵀv to rows start..start+len(v)-1 and
+// columns [c0, c1) of the row-major matrix a with row stride stride
+func applyReflector(v, a []float64, stride, start, c0, c1 int) {
+	vv := 0.0
+	for _, x := range v {
+		vv += x * x
+	}
+	if vv == 0 {
+		return
+	}
+	for c := c0; c < c1; c++ {
+		dot := 0.0
+		for r, x := range v {
+			dot += x * a[(start+r)*stride+c]
+		}
+		scale := 2 * dot / vv
+		for r, x := range v {
+			a[(start+r)*stride+c] -= scale * x
+		}
+	}
+}
+
+// Rank estimates the rank of the factored matrix as the number of diagonal
+// entries of R that are not negligible next to the largest one.
+func (d *QRDecomposition) Rank() int {
+	k := min(d.m, d.n)
+	if k == 0 {
+		return 0
+	}
+	tol := float64(max(d.m, d.n)) * math.Abs(d.R.data[0]) * 1e-15
+	rank := 0
+	for i := 0; i < k; i++ {
+		if math.Abs(d.R.data[i*d.n+i]) > tol {
+			rank++
+		}
+	}
+	return rank
+}
+
+// LeastSquares returns x minimizing ||Ax - b|| for the factored matrix A. If
+// A is rank deficient, x is the basic solution with zeros in the columns that
+// were pivoted last.
+func (d *QRDecomposition) LeastSquares(b []float64) ([]float64, error) {
+	if len(b) != d.m {
+		return nil, &ErrDimensionMismatch{
+			"LeastSquares", []int{d.m, d.n}, []int{len(b)}}
+	}
+
+	// Qᵀb, by applying the reflectors in order
+	qtb := make([]float64, d.m)
+	copy(qtb, b)
+	for j, v := range d.reflectors {
+		if v != nil {
+			applyReflector(v, qtb, 1, j, 0, 1)
+		}
+	}
+
+	// Back substitute R[:r, :r] z = (Qᵀb)[:r]
+	r := d.Rank()
+	z := make([]float64, r)
+	for i := r - 1; i >= 0; i-- {
+		sum := qtb[i]
+		for k := i + 1; k < r; k++ {
+			sum -= d.R.data[i*d.n+k] * z[k]
+		}
+		z[i] = sum / d.R.data[i*d.n+i]
+	}
+
+	x := make([]float64, d.n)
+	for i, value := range z {
+		x[d.Perm[i]] = value
+	}
+	return x, nil
+}
+
+// LeastSquares returns x minimizing ||Ax - b|| for the 2-D Matrix A.
+func (A *Matrix) LeastSquares(b []float64) ([]float64, error) {
+	d, err := A.QR()
+	if err != nil {
+		return nil, err
+	}
+	return d.LeastSquares(b)
+}
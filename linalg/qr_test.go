@@ -0,0 +1,119 @@
+package linalg
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func randomMatrix(m, n int) *Matrix {
+	A := &Matrix{make([]float64, m*n), []int{m, n}}
+	for i := range A.data {
+		A.data[i] = rand.Float64()*2 - 1
+	}
+	return A
+}
+
+// TestQR checks that AP = QR with orthonormal Q and upper triangular R
+func TestQR(t *testing.T) {
+	t.Parallel()
+
+	rankDeficient := randomMatrix(6, 4)
+	for i := 0; i < 6; i++ {
+		// Column 3 = column 0 + column 1
+		rankDeficient.data[i*4+3] = rankDeficient.data[i*4] + rankDeficient.data[i*4+1]
+	}
+
+	tests := []struct {
+		name     string
+		A        *Matrix
+		wantRank int
+	}{
+		{"Square", randomMatrix(5, 5), 5},
+		{"Tall", randomMatrix(12, 4), 4},
+		{"Wide", randomMatrix(3, 7), 3},
+		{"RankDeficient", rankDeficient, 3},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			d, err := test.A.QR()
+			if err != nil {
+				t.Fatalf(`QR failed: %v`, err)
+			}
+			m, n := test.A.dims[0], test.A.dims[1]
+			k := min(m, n)
+
+			QR := d.Q.Multiply(d.R)
+			for i := 0; i < m; i++ {
+				for j := 0; j < n; j++ {
+					want := test.A.Get(i, d.Perm[j])
+					if math.Abs(QR.Get(i, j)-want) > 1e-12 {
+						t.Fatalf(`(QR)[%d][%d] = %f, expected %f`,
+							i, j, QR.Get(i, j), want)
+					}
+				}
+			}
+
+			for a := 0; a < k; a++ {
+				for b := 0; b < k; b++ {
+					dot := 0.0
+					for i := 0; i < m; i++ {
+						dot += d.Q.Get(i, a) * d.Q.Get(i, b)
+					}
+					want := 0.0
+					if a == b {
+						want = 1
+					}
+					if math.Abs(dot-want) > 1e-12 {
+						t.Fatalf(`Columns %d and %d of Q have dot product %g`, a, b, dot)
+					}
+				}
+				for j := 0; j < a; j++ {
+					if d.R.Get(a, j) != 0 {
+						t.Fatalf(`R[%d][%d] = %g is below the diagonal`, a, j, d.R.Get(a, j))
+					}
+				}
+			}
+
+			if rank := d.Rank(); rank != test.wantRank {
+				t.Fatalf(`Expected rank %d, got %d`, test.wantRank, rank)
+			}
+		})
+	}
+}
+
+// TestLeastSquares fits a line through noisy points and through exact points
+func TestLeastSquares(t *testing.T) {
+	t.Parallel()
+
+	// Exact fit of y = 2 + 3x
+	A := &Matrix{[]float64{1, 0, 1, 1, 1, 2, 1, 3}, []int{4, 2}}
+	x, err := A.LeastSquares([]float64{2, 5, 8, 11})
+	if err != nil {
+		t.Fatalf(`LeastSquares failed: %v`, err)
+	}
+	if math.Abs(x[0]-2) > 1e-12 || math.Abs(x[1]-3) > 1e-12 {
+		t.Fatalf(`Expected [2 3], got %v`, x)
+	}
+
+	// Best fit through (0, 0), (1, 1), (2, 1): y = 1/6 + x/2
+	A = &Matrix{[]float64{1, 0, 1, 1, 1, 2}, []int{3, 2}}
+	x, err = A.LeastSquares([]float64{0, 1, 1})
+	if err != nil {
+		t.Fatalf(`LeastSquares failed: %v`, err)
+	}
+	if math.Abs(x[0]-1.0/6) > 1e-12 || math.Abs(x[1]-0.5) > 1e-12 {
+		t.Fatalf(`Expected [0.1667 0.5], got %v`, x)
+	}
+
+	if _, err := A.LeastSquares([]float64{1, 2}); err == nil {
+		t.Fatalf(`Expected error for mismatched right-hand side`)
+	}
+	B := &Matrix{[]float64{1, 2, 3}, []int{3}}
+	if _, err := B.QR(); err == nil {
+		t.Fatalf(`Expected error for 1-D Matrix`)
+	}
+}
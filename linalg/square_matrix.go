@@ -1,9 +1,9 @@
 package linalg
 
 import (
+	"context"
 	"fmt"
 	"strings"
-	"sync"
 )
 
 // SquareMatrix is a struct that represents an n x n matrix.
@@ -13,6 +13,83 @@ type SquareMatrix struct {
 }
 
 
+// NewSquareMatrix returns a zero-filled n x n SquareMatrix.
+func NewSquareMatrix(n int) (*SquareMatrix, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("SquareMatrix cannot have negative size %d", n)
+	}
+	return &SquareMatrix{make([]float64, n*n), n}, nil
+}
+
+// NewSquareMatrixFromData returns an n x n SquareMatrix backed by data, which
+// is laid out in row-major order.
+func NewSquareMatrixFromData(data []float64, n int) (*SquareMatrix, error) {
+	if n < 0 || len(data) != n*n {
+		return nil, fmt.Errorf(
+			"data of length %d cannot fill a %dx%d SquareMatrix", len(data), n, n)
+	}
+	return &SquareMatrix{data, n}, nil
+}
+
+// FromRows returns a SquareMatrix holding a copy of rows. Every row must have
+// len(rows) entries.
+func FromRows(rows [][]float64) (*SquareMatrix, error) {
+	n := len(rows)
+	data := make([]float64, 0, n*n)
+	for i, row := range rows {
+		if len(row) != n {
+			return nil, fmt.Errorf(
+				"row %d has length %d, expected %d", i, len(row), n)
+		}
+		data = append(data, row...)
+	}
+	return &SquareMatrix{data, n}, nil
+}
+
+// Zeros returns an n x n SquareMatrix of zeros.
+func Zeros(n int) (*SquareMatrix, error) {
+	return NewSquareMatrix(n)
+}
+
+// Ones returns an n x n SquareMatrix of ones.
+func Ones(n int) (*SquareMatrix, error) {
+	A, err := NewSquareMatrix(n)
+	if err != nil {
+		return nil, err
+	}
+	for i := range A.data {
+		A.data[i] = 1
+	}
+	return A, nil
+}
+
+// Identity returns the n x n identity SquareMatrix.
+func Identity(n int) (*SquareMatrix, error) {
+	A, err := NewSquareMatrix(n)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		A.data[i*n+i] = 1
+	}
+	return A, nil
+}
+
+// Diag returns a SquareMatrix with values along its diagonal.
+func Diag(values ...float64) *SquareMatrix {
+	n := len(values)
+	A := &SquareMatrix{make([]float64, n*n), n}
+	for i, value := range values {
+		A.data[i*n+i] = value
+	}
+	return A
+}
+
+// N returns the number of rows (and columns) of A.
+func (A SquareMatrix) N() int {
+	return A.n
+}
+
 func (A SquareMatrix) Size() int {
 	return A.n*A.n
 }
@@ -30,6 +107,14 @@ func (A SquareMatrix) Get(i, j int) float64 {
     return A.data[i*A.n + j]
 }
 
+// TryGet is like Get but returns an *ErrIndexOutOfRange instead of panicking.
+func (A SquareMatrix) TryGet(i, j int) (float64, error) {
+	if !A.isValidIndex(i, j) {
+		return 0, &ErrIndexOutOfRange{[]int{i, j}, []int{A.n, A.n}}
+	}
+	return A.Get(i, j), nil
+}
+
 func (A *SquareMatrix) Set(x,y int, value float64) {
 	if !A.isValidIndex(x, y) {
 		panic(fmt.Sprintf(
@@ -38,6 +123,15 @@ func (A *SquareMatrix) Set(x,y int, value float64) {
 	A.data[x*A.n + y] = value
 }
 
+// TrySet is like Set but returns an *ErrIndexOutOfRange instead of panicking.
+func (A *SquareMatrix) TrySet(i, j int, value float64) error {
+	if !A.isValidIndex(i, j) {
+		return &ErrIndexOutOfRange{[]int{i, j}, []int{A.n, A.n}}
+	}
+	A.Set(i, j, value)
+	return nil
+}
+
 func (A SquareMatrix) String() string {
 	strPieces := []string{}
 	for i:=0; i<A.n; i++ {
@@ -68,6 +162,16 @@ func (A *SquareMatrix) Add(B *SquareMatrix) *SquareMatrix {
 	return &C
 }
 
+// TryAdd is like Add but returns an *ErrDimensionMismatch instead of
+// panicking.
+func (A *SquareMatrix) TryAdd(B *SquareMatrix) (*SquareMatrix, error) {
+	if A.n != B.n {
+		return nil, &ErrDimensionMismatch{
+			"Add", []int{A.n, A.n}, []int{B.n, B.n}}
+	}
+	return A.Add(B), nil
+}
+
 func (A *SquareMatrix) Multiply(B *SquareMatrix) *SquareMatrix {
 	if A.n != B.n {
 		panic(fmt.Sprintf(
@@ -77,17 +181,33 @@ func (A *SquareMatrix) Multiply(B *SquareMatrix) *SquareMatrix {
 	return SquareMatrixMultiplyDense(A, B)
 }
 
+// TryMultiply is like Multiply but returns an *ErrDimensionMismatch instead of
+// panicking.
+func (A *SquareMatrix) TryMultiply(B *SquareMatrix) (*SquareMatrix, error) {
+	if A.n != B.n {
+		return nil, &ErrDimensionMismatch{
+			"Multiply", []int{A.n, A.n}, []int{B.n, B.n}}
+	}
+	return A.Multiply(B), nil
+}
+
+// LeftMultiply returns the row vector v multiplied by A, i.e. vA.
+func (A *SquareMatrix) LeftMultiply(v []float64) []float64 {
+	if len(v) != A.n {
+		panic(fmt.Sprintf(
+			"Vector of length %d cannot be multiplied by a %dx%d SquareMatrix",
+			len(v), A.n, A.n))
+	}
+	row := Matrix{v, []int{1, A.n}}
+	B := Matrix{A.data, []int{A.n, A.n}}
+	return row.Multiply(&B).data
+}
+
+// SquareMatrixMultiplySimple multiplies A and B on the calling goroutine with
+// the cache-tiled kernel.
 func SquareMatrixMultiplySimple(A *SquareMatrix, B *SquareMatrix) *SquareMatrix {
 	C := SquareMatrix{make([]float64, A.Size()), A.n}
-	for i:=0; i<A.n; i++ {
-		for k:=0; k<A.n; k++ {
-			var value float64 = 0
-			for j:=0; j<A.n; j++ {
-				value += A.Get(i, j) * B.Get(j, k)
-			}
-			C.Set(i, k, value)
-		}
-	}
+	tiledMultiplyAdd(A.data, B.data, C.data, A.n, CurrentTuning().TileSize)
 	return &C
 }
 
@@ -100,6 +220,32 @@ func (A *SquareMatrix) SetSubMatrix(subMatrix *SquareMatrix, i, j int) {
 	}
 }
 
+// SubMatrix returns the len(rows) x len(cols) Matrix of the entries of A in
+// the given rows and columns, in the order given.
+func (A *SquareMatrix) SubMatrix(rows, cols []int) *Matrix {
+	sub := Matrix{make([]float64, len(rows)*len(cols)), []int{len(rows), len(cols)}}
+	for a, i := range rows {
+		for b, j := range cols {
+			sub.data[a*len(cols)+b] = A.Get(i, j)
+		}
+	}
+	return &sub
+}
+
+// PrincipalSubMatrix returns the SquareMatrix of the entries of A whose row
+// and column are both in indices, in the order given.
+func (A *SquareMatrix) PrincipalSubMatrix(indices []int) *SquareMatrix {
+	sub := A.SubMatrix(indices, indices)
+	return &SquareMatrix{sub.data, len(indices)}
+}
+
+// ToMatrix returns a copy of A as a 2-D Matrix.
+func (A *SquareMatrix) ToMatrix() *Matrix {
+	data := make([]float64, len(A.data))
+	copy(data, A.data)
+	return &Matrix{data, []int{A.n, A.n}}
+}
+
 // getSubMatrix returns a the s x s subMatrix starting at (i, j) in A
 func getSubMatrix(A *SquareMatrix, i, j, s int) *SquareMatrix {
 	subMatrix := SquareMatrix{make([]float64, s*s), s}
@@ -111,115 +257,137 @@ func getSubMatrix(A *SquareMatrix, i, j, s int) *SquareMatrix {
 	return &subMatrix
 }
 
-// chooseP returns the optimal value of p for a given n
-func chooseP(n int) int {
-	// TODO: Improve on this
-	if n % 2 == 1 || n < 100 {
-		return 1
+// chooseP returns the number of blocks p per side to split an n x n multiply
+// into, so that blocks are about t.BlockSize across and, where blocks stay at
+// least a tile across, there are enough of them to keep t.Workers busy
+func chooseP(n int, t Tuning) int {
+	if t.BlockCount > 0 {
+		return max(min(t.BlockCount, n), 1)
 	}
-
-	if n == 100 || n == 500 {
-		return 4
-	} else if n == 1024 {
-		return 16
-	} else if n == 2048 {
-		return 32
+	if t.BlockSize <= 0 || n < 2*t.BlockSize {
+		return 1
 	}
 
-	p := 1
-	for n >= 100 {
-		n /= 10
-		p *= 2
+	p := (n + t.BlockSize - 1) / t.BlockSize
+	for p*p < t.Workers && n/(p+1) >= t.TileSize {
+		p++
 	}
 	return p
 }
 
 func SquareMatrixMultiplyDense(A *SquareMatrix, B *SquareMatrix) *SquareMatrix {
-	// Split A and B into p*p submatrices each of size n/p x n/p
-	n := A.n
-	p := chooseP(n)
+	C, _ := squareMatrixMultiplyDenseContext(context.Background(), A, B, nil)
+	return C
+}
 
-	// fmt.Printf("n: %d, p: %d\n", n, p)
+// MultiplyContext is like Multiply but stops between blocks and returns
+// ctx.Err() once ctx is done. If progress is non-nil it is called as each
+// block of the result is finished.
+func (A *SquareMatrix) MultiplyContext(
+	ctx context.Context, B *SquareMatrix, progress ProgressFunc,
+) (*SquareMatrix, error) {
+	if A.n != B.n {
+		return nil, &ErrDimensionMismatch{
+			"Multiply", []int{A.n, A.n}, []int{B.n, B.n}}
+	}
+	return squareMatrixMultiplyDenseContext(ctx, A, B, progress)
+}
+
+func squareMatrixMultiplyDenseContext(
+	ctx context.Context, A *SquareMatrix, B *SquareMatrix, progress ProgressFunc,
+) (*SquareMatrix, error) {
+	// Split A and B into p*p blocks, padded up to ceil(n/p) x ceil(n/p)
+	p := chooseP(A.n, CurrentTuning())
+	return squareMatrixMultiplyPadded(ctx, A, B, p, progress)
+}
 
+// squareMatrixMultiplyPadded multiplies A and B as p x p blocks, padding
+// them with zeros when n is not a multiple of p
+func squareMatrixMultiplyPadded(
+	ctx context.Context, A *SquareMatrix, B *SquareMatrix, p int,
+	progress ProgressFunc,
+) (*SquareMatrix, error) {
+	n := A.n
 	if p == 1 {
-		return SquareMatrixMultiplySimple(A, B)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		C := SquareMatrixMultiplySimple(A, B)
+		(&progressCounter{total: 1, report: progress}).step()
+		return C, nil
+	}
+
+	// Pad with zeros up to the next multiple of p so the blocks are equal in
+	// size, then crop the padding back off the result
+	if n % p != 0 {
+		m := (n/p + 1) * p
+		C, err := squareMatrixMultiplyBlocked(
+			ctx, padSquareMatrix(A, m), padSquareMatrix(B, m), p, progress)
+		if err != nil {
+			return nil, err
+		}
+		return getSubMatrix(C, 0, 0, n), nil
 	}
+	return squareMatrixMultiplyBlocked(ctx, A, B, p, progress)
+}
+
+// padSquareMatrix returns a copy of A in the top left of an m x m zero matrix
+func padSquareMatrix(A *SquareMatrix, m int) *SquareMatrix {
+	padded := SquareMatrix{make([]float64, m*m), m}
+	for i:=0; i<A.n; i++ {
+		copy(padded.data[i*m:i*m+A.n], A.data[i*A.n:(i+1)*A.n])
+	}
+	return &padded
+}
+
+// squareMatrixMultiplyBlocked multiplies A and B concurrently as p x p grids of
+// submatrices. A.n must be divisible by p.
+func squareMatrixMultiplyBlocked(
+	ctx context.Context, A *SquareMatrix, B *SquareMatrix, p int,
+	progress ProgressFunc,
+) (*SquareMatrix, error) {
+	n := A.n
+	s := n / p
+	tile := CurrentTuning().TileSize
+	pool := DefaultPool()
 
 	// Create a new result SquareMatrix C of size n x n
 	C := SquareMatrix{make([]float64, A.Size()), n}
 
-	// Store submatrices of A and B in maps
-	subMatricesA := make(map[string]*SquareMatrix)
-	AMutex := sync.RWMutex{}
-	subMatricesB := make(map[string]*SquareMatrix)
-	BMutex := sync.RWMutex{}
-	subMatrixDones := make(chan bool)
-
-	// Create goroutines to calculate submatrices concurrently
-	for i:=0; i<p; i++ {
-		for j:=0; j<p; j++ {
-			go func(i, j, p int) {
-				AMutex.Lock()
-				Aij := getSubMatrix(A, i*n/p, j*n/p, n/p)
-				subMatricesA[fmt.Sprintf("%d,%d", i, j)] = Aij
-				AMutex.Unlock()
-				subMatrixDones <- true
-			}(i, j, p)
-			
-			go func(i, j, p int) {
-				BMutex.Lock()
-				Bij := getSubMatrix(B, i*n/p, j*n/p, n/p)
-				subMatricesB[fmt.Sprintf("%d,%d", i, j)] = Bij
-				BMutex.Unlock()
-				subMatrixDones <- true
-			}(i, j, p)
+	// Copy out the p^2 submatrices of A and B, with the block at (i, j)
+	// stored at index i*p + j
+	subMatricesA := make([]*SquareMatrix, p*p)
+	subMatricesB := make([]*SquareMatrix, p*p)
+	err := pool.RunContext(ctx, 2*p*p, func(t int) {
+		idx := t % (p*p)
+		i, j := idx/p, idx%p
+		if t < p*p {
+			subMatricesA[idx] = getSubMatrix(A, i*s, j*s, s)
+		} else {
+			subMatricesB[idx] = getSubMatrix(B, i*s, j*s, s)
 		}
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Wait for all of A and B's p^2 submatrices to be calculated
-	for i:=0; i<2*p*p; i++ {
-		<-subMatrixDones
-	}
-
-	// Print submatrices
-	// fmt.Println("Submatrices A:")
-	// count := 0
-	// for key, value := range subMatricesA {
-	// 	if count == 10 {
-	// 		break
-	// 	}
-	// 	fmt.Printf("%s:\n%s\n    ---    \n", key, value.String())
-	// 	count++
-	// }
-	// fmt.Println("Submatrices B:")
-	// for key, value := range subMatricesB {
-	// 	fmt.Printf("%s:\n%s\n    ---    \n", key, value.String())
-	// }
-
-	// Create a channel to store results of submatrix multiplications
-	subMatrixResults := make(chan struct{i, j int; subMatrix *SquareMatrix})
-
-	// Create goroutines to multiply submatrices concurrently
-	for i:=0; i<p; i++ {
-		for j:=0; j<p; j++ {
-			go func(i, j, p int) {
-				Cij := &SquareMatrix{make([]float64, (n/p)*(n/p)), n/p}
-				for k:=0; k<p; k++ {
-					// TODO: Do I need to use locks here?
-					Aik := subMatricesA[fmt.Sprintf("%d,%d", i, k)]
-					Bkj := subMatricesB[fmt.Sprintf("%d,%d", k, j)]
-					Cij = SquareMatrixMultiplySimple(Aik, Bkj).Add(Cij)
-				}
-				subMatrixResults <- struct{i, j int; subMatrix *SquareMatrix}{i, j, Cij}
-			}(i, j, p)
+	// Compute each block Cij = sum_k Aik*Bkj and write it into its own region
+	// of C, so no two tasks touch the same elements
+	counter := &progressCounter{total: p*p, report: progress}
+	err = pool.RunContext(ctx, p*p, func(idx int) {
+		i, j := idx/p, idx%p
+		Cij := &SquareMatrix{make([]float64, s*s), s}
+		for k:=0; k<p; k++ {
+			Aik := subMatricesA[i*p+k]
+			Bkj := subMatricesB[k*p+j]
+			tiledMultiplyAdd(Aik.data, Bkj.data, Cij.data, s, tile)
 		}
+		C.SetSubMatrix(Cij, i*s, j*s)
+		counter.step()
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Process all p^2 submatrix results as they come in
-	for i:=0; i<p*p; i++ {
-		result := <-subMatrixResults
-		C.SetSubMatrix(result.subMatrix, result.i*(n/p), result.j*(n/p))
-	}
-
-	return &C
+	return &C, nil
 }
@@ -1,8 +1,12 @@
 package linalg
 
 import (
+	"context"
+	"errors"
+	"math"
 	"math/rand"
 	"reflect"
+	"sync/atomic"
 	"testing"
 )
 
@@ -224,6 +228,7 @@ func TestSqMultiply(t *testing.T) {
 	t.Parallel()
 
 	largeTestMatrix := createSquareMatrix(random, 1024)
+	raggedTestMatrix := createSquareMatrix(random, 101)
 
 	tests := []struct{
 		name string
@@ -255,6 +260,12 @@ func TestSqMultiply(t *testing.T) {
 			createSquareMatrix(identity, 100),
 			createSquareMatrix(ones, 100),
 		},
+		{
+			"RaggedShouldPass",
+			raggedTestMatrix,
+			createSquareMatrix(identity, 101),
+			raggedTestMatrix,
+		},
 		{
 			"LargestShouldPass",
 			largeTestMatrix,
@@ -276,6 +287,238 @@ func TestSqMultiply(t *testing.T) {
 	}
 }
 
+// TestSqMultiplyDenseRagged compares the blocked multiply against
+// SquareMatrixMultiplySimple for sizes not divisible by the block count. The
+// block count is fixed rather than tuned so that padding runs on every host.
+func TestSqMultiplyDenseRagged(t *testing.T) {
+	t.Parallel()
+
+	for _, n := range []int{101, 103, 150, 211} {
+		A := createSquareMatrix(random, n)
+		B := createSquareMatrix(random, n)
+		want := SquareMatrixMultiplySimple(A, B)
+		for _, p := range []int{2, 3, 4, 7} {
+			if n%p == 0 {
+				continue
+			}
+			result, err := squareMatrixMultiplyPadded(context.Background(), A, B, p, nil)
+			if err != nil {
+				t.Fatalf(`n=%d, p=%d: %v`, n, p, err)
+			}
+			if result.n != n {
+				t.Fatalf(`Expected %dx%d result, got %dx%d`, n, n, result.n, result.n)
+			}
+			for i := range want.data {
+				if math.Abs(want.data[i]-result.data[i]) > 1e-9 {
+					t.Fatalf(`n=%d, p=%d: element %d differs. Expected %f, got %f`,
+						n, p, i, want.data[i], result.data[i])
+				}
+			}
+		}
+
+		I := createSquareMatrix(identity, n)
+		result, err := squareMatrixMultiplyPadded(context.Background(), A, I, 3, nil)
+		if err != nil || !reflect.DeepEqual(result.data, A.data) {
+			t.Fatalf(`n=%d: Expected A * I = A (%v)`, n, err)
+		}
+	}
+}
+
+// TestSqSubMatrix calls SquareMatrix.SubMatrix and
+// SquareMatrix.PrincipalSubMatrix
+func TestSqSubMatrix(t *testing.T) {
+	t.Parallel()
+	A := &SquareMatrix{[]float64{1, 2, 3, 4, 5, 6, 7, 8, 9}, 3}
+
+	sub := A.SubMatrix([]int{2, 0}, []int{1})
+	if !reflect.DeepEqual(sub.data, []float64{8, 2}) ||
+		!reflect.DeepEqual(sub.dims, []int{2, 1}) {
+		t.Fatalf(`Expected [8 2] with dimensions [2 1], got %v with %v`,
+			sub.data, sub.dims)
+	}
+
+	principal := A.PrincipalSubMatrix([]int{0, 2})
+	if !reflect.DeepEqual(principal.data, []float64{1, 3, 7, 9}) || principal.n != 2 {
+		t.Fatalf(`Expected [1 3 7 9], got %v`, principal.data)
+	}
+
+	M := A.ToMatrix()
+	M.data[0] = 100
+	if A.data[0] != 1 || !reflect.DeepEqual(M.dims, []int{3, 3}) {
+		t.Fatalf(`Expected ToMatrix to copy A into a 3x3 Matrix`)
+	}
+}
+
+// TestSqLeftMultiply calls SquareMatrix.LeftMultiply
+func TestSqLeftMultiply(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct{
+		name string
+		A *SquareMatrix
+		v []float64
+		want []float64
+	}{
+		{
+			"2x2",
+			&SquareMatrix{[]float64{1, 2, 3, 4}, 2},
+			[]float64{1, 1},
+			[]float64{4, 6},
+		},
+		{
+			"Identity",
+			createSquareMatrix(identity, 3),
+			[]float64{0.2, 0.3, 0.5},
+			[]float64{0.2, 0.3, 0.5},
+		},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			result := test.A.LeftMultiply(test.v)
+			if !reflect.DeepEqual(result, test.want) {
+				t.Fatalf(`Failed to left multiply %v. Expected %v, got %v`,
+					test.v, test.want, result)
+			}
+		})
+	}
+}
+
+// TestSqConstructors calls the exported SquareMatrix constructors
+func TestSqConstructors(t *testing.T) {
+	t.Parallel()
+
+	fromRows, err := FromRows([][]float64{{1, 2}, {3, 4}})
+	if err != nil {
+		t.Fatalf(`FromRows failed: %v`, err)
+	}
+	identity3, _ := Identity(3)
+	zeros2, _ := Zeros(2)
+	ones2, _ := Ones(2)
+
+	tests := []struct{
+		name string
+		A *SquareMatrix
+		want []float64
+	}{
+		{"FromRows", fromRows, []float64{1, 2, 3, 4}},
+		{"Identity", identity3, []float64{1, 0, 0, 0, 1, 0, 0, 0, 1}},
+		{"Zeros", zeros2, []float64{0, 0, 0, 0}},
+		{"Ones", ones2, []float64{1, 1, 1, 1}},
+		{"Diag", Diag(2, 3), []float64{2, 0, 0, 3}},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			if !reflect.DeepEqual(test.A.data, test.want) {
+				t.Fatalf(`Expected %v, got %v`, test.want, test.A.data)
+			}
+		})
+	}
+
+	if _, err := FromRows([][]float64{{1, 2}, {3}}); err == nil {
+		t.Fatalf(`Expected error for ragged rows`)
+	}
+	if _, err := NewSquareMatrixFromData([]float64{1, 2, 3}, 2); err == nil {
+		t.Fatalf(`Expected error for mismatched data length`)
+	}
+	if _, err := NewSquareMatrix(-1); err == nil {
+		t.Fatalf(`Expected error for negative size`)
+	}
+}
+
+// TestSqTryErrors checks that the SquareMatrix Try methods return typed errors
+func TestSqTryErrors(t *testing.T) {
+	t.Parallel()
+	A := createSquareMatrix(ones, 2)
+	B := createSquareMatrix(ones, 3)
+
+	var dimErr *ErrDimensionMismatch
+	if _, err := A.TryMultiply(B); !errors.As(err, &dimErr) {
+		t.Fatalf(`Expected ErrDimensionMismatch, got %v`, err)
+	}
+	if _, err := A.TryAdd(B); !errors.As(err, &dimErr) {
+		t.Fatalf(`Expected ErrDimensionMismatch, got %v`, err)
+	}
+
+	var indexErr *ErrIndexOutOfRange
+	if _, err := A.TryGet(0, 2); !errors.As(err, &indexErr) {
+		t.Fatalf(`Expected ErrIndexOutOfRange, got %v`, err)
+	}
+	if err := A.TrySet(-1, 0, 1); !errors.As(err, &indexErr) {
+		t.Fatalf(`Expected ErrIndexOutOfRange, got %v`, err)
+	}
+
+	C, err := A.TryAdd(A)
+	if err != nil || C.Get(1, 1) != 2 {
+		t.Fatalf(`Expected TryAdd to succeed, got %v`, err)
+	}
+}
+
+// TestSqMultiplyContext calls SquareMatrix.MultiplyContext and checks
+// progress reporting and cancellation between blocks
+func TestSqMultiplyContext(t *testing.T) {
+	t.Parallel()
+	A := createSquareMatrix(random, 64)
+	I := createSquareMatrix(identity, 64)
+
+	var calls, lastDone, lastTotal int
+	result, err := A.MultiplyContext(context.Background(), I,
+		func(done, total int) {
+			calls++
+			lastDone, lastTotal = done, total
+		})
+	if err != nil {
+		t.Fatalf(`MultiplyContext failed: %v`, err)
+	}
+	if !reflect.DeepEqual(result.data, A.data) {
+		t.Fatalf(`Expected A*I = A`)
+	}
+	if calls == 0 || lastDone != lastTotal || calls != lastTotal {
+		t.Fatalf(`Expected %d progress calls ending at done == total, got %d ending at %d/%d`,
+			lastTotal, calls, lastDone, lastTotal)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := A.MultiplyContext(cancelled, I, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf(`Expected context.Canceled, got %v`, err)
+	}
+
+	if _, err := A.MultiplyContext(context.Background(), createSquareMatrix(ones, 3), nil); err == nil {
+		t.Fatalf(`Expected error for mismatched sizes`)
+	}
+}
+
+// TestSqMultiplyBlockedCancel cancels a blocked multiply from its first
+// progress report. It pins the default pool to one worker, so at most two
+// blocks are in flight when cancel runs, and so cannot run in parallel.
+func TestSqMultiplyBlockedCancel(t *testing.T) {
+	defer SetTuning(Tuning{})
+	SetTuning(Tuning{Workers: 1})
+	A := createSquareMatrix(random, 64)
+	I := createSquareMatrix(identity, 64)
+
+	// Cancel from the first progress report of a 4x4 grid of blocks
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var blocks atomic.Int64
+	_, err := squareMatrixMultiplyBlocked(ctx, A, I, 4, func(done, total int) {
+		blocks.Store(int64(done))
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf(`Expected context.Canceled, got %v`, err)
+	}
+	if blocks.Load() == 16 {
+		t.Fatalf(`Expected cancellation to skip some of the 16 blocks`)
+	}
+}
+
 // ===========================================================================
 func BenchmarkSqMultiply(b *testing.B) {
 	size := 2048
@@ -0,0 +1,81 @@
+package linalg
+
+// SquareMatrixMultiplyStrassen multiplies A and B with the Strassen-Winograd
+// algorithm, recursing until the sub-problems are no larger than
+// CurrentTuning().StrassenCrossover and then using SquareMatrixMultiplyDense.
+// Odd sizes are padded by one row and column at the level they occur, and the
+// seven sub-products at each level run on the DefaultPool.
+//
+// Strassen trades seven half-size products for eight at the cost of extra
+// additions, so results can differ from SquareMatrixMultiplySimple by more
+// than rounding in the last place.
+func SquareMatrixMultiplyStrassen(A *SquareMatrix, B *SquareMatrix) *SquareMatrix {
+	return strassenMultiply(A, B, CurrentTuning().StrassenCrossover)
+}
+
+func strassenMultiply(A *SquareMatrix, B *SquareMatrix, crossover int) *SquareMatrix {
+	n := A.n
+	if n <= max(crossover, 1) {
+		return SquareMatrixMultiplyDense(A, B)
+	}
+	if n%2 == 1 {
+		C := strassenMultiply(
+			padSquareMatrix(A, n+1), padSquareMatrix(B, n+1), crossover)
+		return getSubMatrix(C, 0, 0, n)
+	}
+
+	h := n / 2
+	A11, A12 := getSubMatrix(A, 0, 0, h), getSubMatrix(A, 0, h, h)
+	A21, A22 := getSubMatrix(A, h, 0, h), getSubMatrix(A, h, h, h)
+	B11, B12 := getSubMatrix(B, 0, 0, h), getSubMatrix(B, 0, h, h)
+	B21, B22 := getSubMatrix(B, h, 0, h), getSubMatrix(B, h, h, h)
+
+	S1 := addSquare(A21, A22)
+	S2 := subSquare(S1, A11)
+	S3 := subSquare(A11, A21)
+	S4 := subSquare(A12, S2)
+	T1 := subSquare(B12, B11)
+	T2 := subSquare(B22, T1)
+	T3 := subSquare(B22, B12)
+	T4 := subSquare(T2, B21)
+
+	factors := [7][2]*SquareMatrix{
+		{A11, B11}, {A12, B21}, {S4, B22}, {A22, T4},
+		{S1, T1}, {S2, T2}, {S3, T3},
+	}
+	var M [7]*SquareMatrix
+	DefaultPool().Run(len(factors), func(i int) {
+		M[i] = strassenMultiply(factors[i][0], factors[i][1], crossover)
+	})
+
+	U2 := addSquare(M[0], M[5])
+	U3 := addSquare(U2, M[6])
+	U4 := addSquare(U2, M[4])
+
+	C := SquareMatrix{make([]float64, n*n), n}
+	C.SetSubMatrix(addSquare(M[0], M[1]), 0, 0)
+	C.SetSubMatrix(addSquare(U4, M[2]), 0, h)
+	C.SetSubMatrix(subSquare(U3, M[3]), h, 0)
+	C.SetSubMatrix(addSquare(U3, M[4]), h, h)
+	return &C
+}
+
+// addSquare returns A + B without modifying either
+func addSquare(A *SquareMatrix, B *SquareMatrix) *SquareMatrix {
+	C := SquareMatrix{make([]float64, A.Size()), A.n}
+	b := B.data[:len(A.data)]
+	for i, a := range A.data {
+		C.data[i] = a + b[i]
+	}
+	return &C
+}
+
+// subSquare returns A - B without modifying either
+func subSquare(A *SquareMatrix, B *SquareMatrix) *SquareMatrix {
+	C := SquareMatrix{make([]float64, A.Size()), A.n}
+	b := B.data[:len(A.data)]
+	for i, a := range A.data {
+		C.data[i] = a - b[i]
+	}
+	return &C
+}
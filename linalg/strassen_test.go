@@ -0,0 +1,66 @@
+package linalg
+
+import (
+	"math"
+	"testing"
+)
+
+// TestStrassenAccuracy compares strassenMultiply against
+// SquareMatrixMultiplySimple and checks that the error stays within a small
+// multiple of machine precision relative to the size of the product
+func TestStrassenAccuracy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		n         int
+		crossover int
+	}{
+		{"BelowCrossover", 20, 32},
+		{"PowerOfTwo", 64, 8},
+		{"Even", 100, 16},
+		{"Odd", 129, 16},
+		{"Deep", 257, 4},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			A := createSquareMatrix(random, test.n)
+			B := createSquareMatrix(random, test.n)
+			want := SquareMatrixMultiplySimple(A, B)
+			result := strassenMultiply(A, B, test.crossover)
+			if result.n != test.n {
+				t.Fatalf(`Expected %dx%d result, got %dx%d`,
+					test.n, test.n, result.n, result.n)
+			}
+
+			// Entries of A and B are in [0, 1) so every entry of AB is at
+			// most n
+			maxErr := 0.0
+			for i := range want.data {
+				maxErr = math.Max(maxErr, math.Abs(want.data[i]-result.data[i]))
+			}
+			relErr := maxErr / float64(test.n)
+			if relErr > 1e-13 {
+				t.Fatalf(`Relative error %g exceeds 1e-13`, relErr)
+			}
+			t.Logf(`n=%d crossover=%d: max abs error %g, relative %g`,
+				test.n, test.crossover, maxErr, relErr)
+		})
+	}
+}
+
+// TestStrassenIdentity checks that multiplying by the identity is exact
+func TestStrassenIdentity(t *testing.T) {
+	t.Parallel()
+	A := createSquareMatrix(random, 70)
+	result := strassenMultiply(A, createSquareMatrix(identity, 70), 8)
+	for i := range A.data {
+		if math.Abs(A.data[i]-result.data[i]) > 1e-12 {
+			t.Fatalf(`Element %d differs. Expected %f, got %f`,
+				i, A.data[i], result.data[i])
+		}
+	}
+}
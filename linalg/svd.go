@@ -0,0 +1,240 @@
+package linalg
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// maxJacobiSweeps bounds the sweeps of one-sided Jacobi rotations in SVD
+const maxJacobiSweeps = 60
+
+// SVDecomposition is the singular value decomposition A = U·diag(S)·VT of an
+// m x n Matrix A. With k = min(m, n), U is m x k, S holds the k singular
+// values in decreasing order and VT is k x n. Columns of U and rows of VT
+// belonging to non-zero singular values are orthonormal; those belonging to
+// zero singular values are zero.
+type SVDecomposition struct {
+	U  *Matrix
+	S  []float64
+	VT *Matrix
+}
+
+// SVD computes the singular value decomposition of the 2-D Matrix A with
+// one-sided Jacobi rotations.
+func (A *Matrix) SVD() (*SVDecomposition, error) {
+	if len(A.dims) != 2 {
+		return nil, fmt.Errorf("SVD requires a 2-D Matrix, got dimensions %v", A.dims)
+	}
+	m, n := A.dims[0], A.dims[1]
+	if m >= n {
+		return jacobiSVD(A.data, m, n)
+	}
+
+	// Decompose Aᵀ = U S Vᵀ, so A = V S Uᵀ
+	at := transpose(A.data, m, n)
+	d, err := jacobiSVD(at, n, m)
+	if err != nil {
+		return nil, err
+	}
+	return &SVDecomposition{
+		U:  &Matrix{transpose(d.VT.data, m, m), []int{m, m}},
+		S:  d.S,
+		VT: &Matrix{transpose(d.U.data, n, m), []int{m, n}},
+	}, nil
+}
+
+// jacobiSVD decomposes the m x n row-major matrix data with m >= n by
+// rotating pairs of columns until they are all orthogonal
+func jacobiSVD(data []float64, m, n int) (*SVDecomposition, error) {
+	const eps = 1e-15
+
+	a := make([]float64, len(data))
+	copy(a, data)
+	v := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		v[i*n+i] = 1
+	}
+
+	converged := false
+	for sweep := 0; sweep < maxJacobiSweeps && !converged; sweep++ {
+		converged = true
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				alpha, beta, gamma := 0.0, 0.0, 0.0
+				for i := 0; i < m; i++ {
+					ap, aq := a[i*n+p], a[i*n+q]
+					alpha += ap * ap
+					beta += aq * aq
+					gamma += ap * aq
+				}
+				if gamma == 0 || math.Abs(gamma) <= eps*math.Sqrt(alpha*beta) {
+					continue
+				}
+				converged = false
+
+				// Rotation that zeroes the off-diagonal of the 2x2 Gram matrix
+				zeta := (beta - alpha) / (2 * gamma)
+				t := 1 / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+				if zeta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(1+t*t)
+				s := c * t
+				rotateColumns(a, m, n, p, q, c, s)
+				rotateColumns(v, n, n, p, q, c, s)
+			}
+		}
+	}
+	if !converged {
+		return nil, &ErrNoConvergence{"Jacobi SVD", maxJacobiSweeps}
+	}
+
+	sigma := make([]float64, n)
+	for j := 0; j < n; j++ {
+		for i := 0; i < m; i++ {
+			sigma[j] += a[i*n+j] * a[i*n+j]
+		}
+		sigma[j] = math.Sqrt(sigma[j])
+	}
+	order := make([]int, n)
+	for j := range order {
+		order[j] = j
+	}
+	slices.SortStableFunc(order, func(x, y int) int {
+		return cmp.Compare(sigma[y], sigma[x])
+	})
+
+	d := &SVDecomposition{
+		U:  &Matrix{make([]float64, m*n), []int{m, n}},
+		S:  make([]float64, n),
+		VT: &Matrix{make([]float64, n*n), []int{n, n}},
+	}
+	for k, j := range order {
+		d.S[k] = sigma[j]
+		if sigma[j] > 0 {
+			for i := 0; i < m; i++ {
+				d.U.data[i*n+k] = a[i*n+j] / sigma[j]
+			}
+			for i := 0; i < n; i++ {
+				d.VT.data[k*n+i] = v[i*n+j]
+			}
+		}
+	}
+	return d, nil
+}
+
+// rotateColumns applies a Givens rotation to columns p and q of the
+// row-major m x n matrix a
+func rotateColumns(a []float64, m, n, p, q int, c, s float64) {
+	for i := 0; i < m; i++ {
+		ap, aq := a[i*n+p], a[i*n+q]
+		a[i*n+p] = c*ap - s*aq
+		a[i*n+q] = s*ap + c*aq
+	}
+}
+
+// transpose returns the transpose of the row-major m x n matrix a
+func transpose(a []float64, m, n int) []float64 {
+	t := make([]float64, len(a))
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			t[j*m+i] = a[i*n+j]
+		}
+	}
+	return t
+}
+
+// tolerance returns the size below which a singular value counts as zero
+func (d *SVDecomposition) tolerance() float64 {
+	if len(d.S) == 0 {
+		return 0
+	}
+	m, n := d.U.dims[0], d.VT.dims[1]
+	return float64(max(m, n)) * d.S[0] * 1e-15
+}
+
+// Rank returns the number of singular values that are not negligible next to
+// the largest one.
+func (d *SVDecomposition) Rank() int {
+	tol := d.tolerance()
+	rank := 0
+	for _, s := range d.S {
+		if s > tol {
+			rank++
+		}
+	}
+	return rank
+}
+
+// ConditionNumber returns the ratio of the largest to the smallest singular
+// value, or +Inf if the matrix is rank deficient.
+func (d *SVDecomposition) ConditionNumber() float64 {
+	if len(d.S) == 0 {
+		return 0
+	}
+	smallest := d.S[len(d.S)-1]
+	if smallest <= d.tolerance() {
+		return math.Inf(1)
+	}
+	return d.S[0] / smallest
+}
+
+// PseudoInverse returns the n x m Moore-Penrose pseudo-inverse V·diag(S⁺)·Uᵀ,
+// treating negligible singular values as zero.
+func (d *SVDecomposition) PseudoInverse() *Matrix {
+	m, k, n := d.U.dims[0], len(d.S), d.VT.dims[1]
+	tol := d.tolerance()
+	pinv := &Matrix{make([]float64, n*m), []int{n, m}}
+	for c, s := range d.S {
+		if s <= tol {
+			continue
+		}
+		for i := 0; i < n; i++ {
+			vic := d.VT.data[c*n+i] / s
+			if vic == 0 {
+				continue
+			}
+			row := pinv.data[i*m : (i+1)*m]
+			for j := range row {
+				row[j] += vic * d.U.data[j*k+c]
+			}
+		}
+	}
+	return pinv
+}
+
+// Truncate returns the decomposition of the best rank-r approximation of the
+// factored matrix, keeping the r largest singular values.
+func (d *SVDecomposition) Truncate(r int) *SVDecomposition {
+	m, k, n := d.U.dims[0], len(d.S), d.VT.dims[1]
+	r = max(min(r, k), 0)
+
+	U := &Matrix{make([]float64, m*r), []int{m, r}}
+	for i := 0; i < m; i++ {
+		copy(U.data[i*r:(i+1)*r], d.U.data[i*k:i*k+r])
+	}
+	VT := &Matrix{make([]float64, r*n), []int{r, n}}
+	copy(VT.data, d.VT.data[:r*n])
+	return &SVDecomposition{U, append([]float64(nil), d.S[:r]...), VT}
+}
+
+// Reconstruct returns U·diag(S)·VT.
+func (d *SVDecomposition) Reconstruct() *Matrix {
+	m, k, n := d.U.dims[0], len(d.S), d.VT.dims[1]
+	A := &Matrix{make([]float64, m*n), []int{m, n}}
+	for c, s := range d.S {
+		for i := 0; i < m; i++ {
+			uic := d.U.data[i*k+c] * s
+			if uic == 0 {
+				continue
+			}
+			row := A.data[i*n : (i+1)*n]
+			for j := range row {
+				row[j] += uic * d.VT.data[c*n+j]
+			}
+		}
+	}
+	return A
+}
@@ -0,0 +1,121 @@
+package linalg
+
+import (
+	"math"
+	"testing"
+)
+
+func matricesClose(A, B *Matrix, tol float64) bool {
+	if len(A.data) != len(B.data) {
+		return false
+	}
+	for i := range A.data {
+		if math.Abs(A.data[i]-B.data[i]) > tol {
+			return false
+		}
+	}
+	return true
+}
+
+// TestSVD checks that A = U·diag(S)·VT with orthonormal factors
+func TestSVD(t *testing.T) {
+	t.Parallel()
+
+	rankOne := &Matrix{[]float64{1, 2, 3, 2, 4, 6, 3, 6, 9, 4, 8, 12}, []int{4, 3}}
+
+	tests := []struct {
+		name     string
+		A        *Matrix
+		wantRank int
+	}{
+		{"Square", randomMatrix(6, 6), 6},
+		{"Tall", randomMatrix(10, 4), 4},
+		{"Wide", randomMatrix(3, 8), 3},
+		{"RankOne", rankOne, 1},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			d, err := test.A.SVD()
+			if err != nil {
+				t.Fatalf(`SVD failed: %v`, err)
+			}
+			if !matricesClose(d.Reconstruct(), test.A, 1e-12) {
+				t.Fatalf(`U·S·VT does not reconstruct A:\n%s`, d.Reconstruct())
+			}
+			for i := 1; i < len(d.S); i++ {
+				if d.S[i] > d.S[i-1] {
+					t.Fatalf(`Singular values not decreasing: %v`, d.S)
+				}
+			}
+			if rank := d.Rank(); rank != test.wantRank {
+				t.Fatalf(`Expected rank %d, got %d (S = %v)`, test.wantRank, rank, d.S)
+			}
+
+			// Rows of VT for non-zero singular values are orthonormal
+			n := d.VT.dims[1]
+			for a := 0; a < d.Rank(); a++ {
+				for b := 0; b < d.Rank(); b++ {
+					dot := 0.0
+					for j := 0; j < n; j++ {
+						dot += d.VT.Get(a, j) * d.VT.Get(b, j)
+					}
+					want := 0.0
+					if a == b {
+						want = 1
+					}
+					if math.Abs(dot-want) > 1e-12 {
+						t.Fatalf(`Rows %d and %d of VT have dot product %g`, a, b, dot)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestSVDDerived calls ConditionNumber, PseudoInverse and Truncate
+func TestSVDDerived(t *testing.T) {
+	t.Parallel()
+
+	A := &Matrix{[]float64{3, 0, 0, 0, 2, 0}, []int{2, 3}}
+	d, err := A.SVD()
+	if err != nil {
+		t.Fatalf(`SVD failed: %v`, err)
+	}
+	if cond := d.ConditionNumber(); math.Abs(cond-1.5) > 1e-12 {
+		t.Fatalf(`Expected condition number 1.5, got %f`, cond)
+	}
+
+	want := &Matrix{[]float64{1.0 / 3, 0, 0, 0.5, 0, 0}, []int{3, 2}}
+	if pinv := d.PseudoInverse(); !matricesClose(pinv, want, 1e-12) {
+		t.Fatalf(`Expected pseudo-inverse\n%s, got\n%s`, want, pinv)
+	}
+
+	low := d.Truncate(1)
+	want = &Matrix{[]float64{3, 0, 0, 0, 0, 0}, []int{2, 3}}
+	if len(low.S) != 1 || !matricesClose(low.Reconstruct(), want, 1e-12) {
+		t.Fatalf(`Expected rank-1 approximation\n%s, got\n%s`, want, low.Reconstruct())
+	}
+
+	singular := &Matrix{[]float64{1, 2, 2, 4}, []int{2, 2}}
+	d, err = singular.SVD()
+	if err != nil {
+		t.Fatalf(`SVD failed: %v`, err)
+	}
+	if !math.IsInf(d.ConditionNumber(), 1) {
+		t.Fatalf(`Expected infinite condition number, got %f`, d.ConditionNumber())
+	}
+
+	// The pseudo-inverse of a random tall matrix is a left inverse
+	B := randomMatrix(7, 3)
+	d, err = B.SVD()
+	if err != nil {
+		t.Fatalf(`SVD failed: %v`, err)
+	}
+	identity3 := &Matrix{[]float64{1, 0, 0, 0, 1, 0, 0, 0, 1}, []int{3, 3}}
+	if !matricesClose(d.PseudoInverse().Multiply(B), identity3, 1e-10) {
+		t.Fatalf(`Expected pinv(B)·B = I`)
+	}
+}
@@ -0,0 +1,233 @@
+package linalg
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tuning holds the host-dependent parameters of the blocked multiply kernels.
+// Zero fields mean "measure the host".
+type Tuning struct {
+	// Workers is the number of goroutines the kernels try to keep busy.
+	Workers int
+	// BlockCount, if positive, fixes p in the p x p grid of blocks used by
+	// SquareMatrixMultiplyDense regardless of the matrix size.
+	BlockCount int
+	// BlockSize is the target edge length of one block. Blocks are sized so
+	// that three of them fit in L2 and a block product outweighs the cost of
+	// scheduling it.
+	BlockSize int
+	// TileSize is the edge length of the tiles the leaf kernel works on so
+	// that three of them fit in L1.
+	TileSize int
+	// StrassenCrossover is the size at or below which
+	// SquareMatrixMultiplyStrassen stops recursing and hands off to
+	// SquareMatrixMultiplyDense.
+	StrassenCrossover int
+}
+
+// Environment variables that override the measured Tuning, for reproducing
+// benchmark numbers across machines.
+const (
+	EnvWorkers    = "LINALG_WORKERS"
+	EnvBlockCount = "LINALG_BLOCK_COUNT"
+	EnvBlockSize  = "LINALG_BLOCK_SIZE"
+	EnvTileSize   = "LINALG_TILE_SIZE"
+
+	EnvStrassenCrossover = "LINALG_STRASSEN_CROSSOVER"
+)
+
+const (
+	defaultL1CacheBytes = 32 << 10
+	defaultL2CacheBytes = 256 << 10
+)
+
+var (
+	hostTuningOnce sync.Once
+	hostTuning     Tuning
+
+	tuningMutex    sync.RWMutex
+	tuningOverride Tuning
+)
+
+// DefaultTuning returns the Tuning measured for this host, with any
+// environment overrides applied. It is computed once per process.
+func DefaultTuning() Tuning {
+	hostTuningOnce.Do(func() {
+		hostTuning = measureHost(tuningFromEnv())
+	})
+	return hostTuning
+}
+
+// SetTuning overrides the Tuning used by the multiply kernels for the rest of
+// the process, resizing DefaultPool to the new number of workers. Zero fields
+// fall back to DefaultTuning.
+func SetTuning(t Tuning) {
+	tuningMutex.Lock()
+	tuningOverride = t
+	tuningMutex.Unlock()
+	if pool := defaultPool.Load(); pool != nil {
+		pool.resize(CurrentTuning().Workers)
+	}
+}
+
+// CurrentTuning returns the Tuning the multiply kernels are using.
+func CurrentTuning() Tuning {
+	tuningMutex.RLock()
+	t := tuningOverride
+	tuningMutex.RUnlock()
+	return t.withDefaults(DefaultTuning())
+}
+
+// withDefaults fills the zero fields of t from defaults
+func (t Tuning) withDefaults(defaults Tuning) Tuning {
+	if t.Workers <= 0 {
+		t.Workers = defaults.Workers
+	}
+	if t.BlockCount <= 0 {
+		t.BlockCount = defaults.BlockCount
+	}
+	if t.BlockSize <= 0 {
+		t.BlockSize = defaults.BlockSize
+	}
+	if t.TileSize <= 0 {
+		t.TileSize = defaults.TileSize
+	}
+	if t.StrassenCrossover <= 0 {
+		t.StrassenCrossover = defaults.StrassenCrossover
+	}
+	return t
+}
+
+// tuningFromEnv reads the LINALG_* environment variables, ignoring any that
+// are not positive integers
+func tuningFromEnv() Tuning {
+	read := func(name string) int {
+		value, err := strconv.Atoi(os.Getenv(name))
+		if err != nil || value <= 0 {
+			return 0
+		}
+		return value
+	}
+	return Tuning{
+		Workers:    read(EnvWorkers),
+		BlockCount: read(EnvBlockCount),
+		BlockSize:  read(EnvBlockSize),
+		TileSize:   read(EnvTileSize),
+
+		StrassenCrossover: read(EnvStrassenCrossover),
+	}
+}
+
+// measureHost fills the zero fields of t from the core count, cache sizes and
+// a short calibration run
+func measureHost(t Tuning) Tuning {
+	if t.Workers <= 0 {
+		t.Workers = runtime.GOMAXPROCS(0)
+	}
+
+	l1, l2 := cacheSizes()
+	if t.TileSize <= 0 {
+		t.TileSize = edgeFitting(l1, 8)
+	}
+	if t.BlockSize <= 0 {
+		t.BlockSize = max(edgeFitting(l2, t.TileSize), calibrateMinBlockSize())
+	}
+	if t.StrassenCrossover <= 0 {
+		// Below a couple of blocks the extra additions cost more than the
+		// saved block products
+		t.StrassenCrossover = 2 * t.BlockSize
+	}
+	return t
+}
+
+// edgeFitting returns the largest multiple of align s such that three s x s
+// float64 matrices fit in cacheBytes
+func edgeFitting(cacheBytes, align int) int {
+	s := int(math.Sqrt(float64(cacheBytes) / 24))
+	return max(align, s/align*align)
+}
+
+// calibrateMinBlockSize times a goroutine round trip against a small multiply
+// and returns the smallest block edge whose product takes ~100x as long as
+// scheduling it
+func calibrateMinBlockSize() int {
+	const spawns = 64
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < spawns; i++ {
+		wg.Add(1)
+		go func() { wg.Done() }()
+	}
+	wg.Wait()
+	spawnCost := float64(time.Since(start)) / spawns
+
+	const s = 48
+	a := make([]float64, s*s)
+	c := make([]float64, s*s)
+	for i := range a {
+		a[i] = 1
+	}
+	start = time.Now()
+	tiledMultiplyAdd(a, a, c, s, s)
+	flopCost := float64(time.Since(start)) / (s * s * s)
+
+	if flopCost <= 0 {
+		return 16
+	}
+	edge := int(math.Cbrt(100 * spawnCost / flopCost))
+	return min(max(edge, 16), 256)
+}
+
+// cacheSizes returns the L1 data and L2 cache sizes in bytes, read from sysfs
+// where available
+func cacheSizes() (l1, l2 int) {
+	l1, l2 = defaultL1CacheBytes, defaultL2CacheBytes
+	dirs, _ := filepath.Glob("/sys/devices/system/cpu/cpu0/cache/index*")
+	for _, dir := range dirs {
+		level := readSysfs(filepath.Join(dir, "level"))
+		kind := readSysfs(filepath.Join(dir, "type"))
+		size, err := parseCacheSize(readSysfs(filepath.Join(dir, "size")))
+		if err != nil {
+			continue
+		}
+		switch {
+		case level == "1" && kind == "Data":
+			l1 = size
+		case level == "2":
+			l2 = size
+		}
+	}
+	return l1, l2
+}
+
+func readSysfs(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// parseCacheSize parses sysfs cache sizes such as "32K" or "1M"
+func parseCacheSize(s string) (int, error) {
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(s, "K"):
+		multiplier, s = 1<<10, strings.TrimSuffix(s, "K")
+	case strings.HasSuffix(s, "M"):
+		multiplier, s = 1<<20, strings.TrimSuffix(s, "M")
+	}
+	size, err := strconv.Atoi(s)
+	if err != nil || size <= 0 {
+		return 0, fmt.Errorf("invalid cache size %q", s)
+	}
+	return size * multiplier, nil
+}
@@ -0,0 +1,98 @@
+package linalg
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestChooseP calls chooseP with fixed Tunings
+func TestChooseP(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		n      int
+		tuning Tuning
+		want   int
+	}{
+		{"Small", 100, Tuning{Workers: 8, BlockSize: 64, TileSize: 16}, 1},
+		{"Divisible", 1024, Tuning{Workers: 1, BlockSize: 64, TileSize: 16}, 16},
+		{"Ragged", 1023, Tuning{Workers: 1, BlockSize: 64, TileSize: 16}, 16},
+		{"ManyWorkers", 256, Tuning{Workers: 64, BlockSize: 128, TileSize: 16}, 8},
+		{"Override", 2048, Tuning{Workers: 1, BlockCount: 32, BlockSize: 64}, 32},
+		{"OverrideClamped", 4, Tuning{BlockCount: 32}, 4},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			if p := chooseP(test.n, test.tuning); p != test.want {
+				t.Fatalf(`Expected p=%d for n=%d, got %d`, test.want, test.n, p)
+			}
+		})
+	}
+}
+
+// TestTuningWithDefaults checks that zero Tuning fields are filled in
+func TestTuningWithDefaults(t *testing.T) {
+	t.Parallel()
+	defaults := Tuning{
+		Workers: 4, BlockCount: 0, BlockSize: 128, TileSize: 32,
+		StrassenCrossover: 256,
+	}
+	got := Tuning{BlockCount: 8, TileSize: 16}.withDefaults(defaults)
+	want := Tuning{
+		Workers: 4, BlockCount: 8, BlockSize: 128, TileSize: 16,
+		StrassenCrossover: 256,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf(`Expected %+v, got %+v`, want, got)
+	}
+
+	host := DefaultTuning()
+	if host.Workers <= 0 || host.BlockSize <= 0 || host.TileSize <= 0 ||
+		host.StrassenCrossover <= 0 {
+		t.Fatalf(`DefaultTuning left fields unset: %+v`, host)
+	}
+}
+
+// TestTuningFromEnv reads overrides from the LINALG_* environment variables
+func TestTuningFromEnv(t *testing.T) {
+	// Cannot call t.Parallel since we are changing the environment
+	t.Setenv(EnvWorkers, "3")
+	t.Setenv(EnvBlockCount, "16")
+	t.Setenv(EnvBlockSize, "not a number")
+	t.Setenv(EnvTileSize, "-8")
+	t.Setenv(EnvStrassenCrossover, "512")
+
+	want := Tuning{Workers: 3, BlockCount: 16, StrassenCrossover: 512}
+	if got := tuningFromEnv(); !reflect.DeepEqual(got, want) {
+		t.Fatalf(`Expected %+v, got %+v`, want, got)
+	}
+}
+
+// TestParseCacheSize parses sysfs cache size strings
+func TestParseCacheSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"32K", 32 << 10, false},
+		{"2M", 2 << 20, false},
+		{"512", 512, false},
+		{"", 0, true},
+		{"xK", 0, true},
+	}
+
+	for _, test := range tests {
+		size, err := parseCacheSize(test.in)
+		if (err != nil) != test.wantErr || size != test.want {
+			t.Fatalf(`parseCacheSize(%q) = %d, %v; expected %d`,
+				test.in, size, err, test.want)
+		}
+	}
+}
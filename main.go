@@ -1,10 +1,281 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	// "github.com/pforderique/markov_chain/matrix"
+	"io"
+	"math"
+	"math/rand/v2"
+	"os"
+	"strings"
+
+	"github.com/pforderique/markov_chain/markov"
 )
 
+const usage = `Usage: markov_chain generate [flags]
+
+Builds an n-gram Markov chain from a text corpus, or loads one saved earlier,
+and prints generated text.
+
+Flags:
+`
+
+// model is what generate saves to and loads from disk
+type model struct {
+	Level string                   `json:"level"`
+	Chain *markov.HigherOrderChain `json:"chain"`
+}
+
 func main() {
-	fmt.Println("yo")
-}
\ No newline at end of file
+	if len(os.Args) < 2 || os.Args[1] != "generate" {
+		fmt.Fprint(os.Stderr, usage)
+		fs, _ := generateFlags(os.Stderr)
+		fs.PrintDefaults()
+		os.Exit(2)
+	}
+	if err := generate(os.Args[2:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "generate:", err)
+		os.Exit(1)
+	}
+}
+
+type generateOptions struct {
+	corpus      string
+	load        string
+	save        string
+	level       string
+	order       int
+	seed        uint64
+	temperature float64
+	length      int
+	prefix      string
+}
+
+// generateFlags returns the flag set of the generate mode, writing errors
+// and usage to output, and the options it parses into
+func generateFlags(output io.Writer) (*flag.FlagSet, *generateOptions) {
+	options := &generateOptions{}
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&options.corpus, "corpus", "", "text file to build the model from, or - for stdin")
+	fs.StringVar(&options.load, "model", "", "model file to load instead of building one")
+	fs.StringVar(&options.save, "save", "", "file to save the model to")
+	fs.StringVar(&options.level, "level", "word", "tokens to model: word or char")
+	fs.IntVar(&options.order, "order", 2, "number of previous tokens the next one depends on")
+	fs.Uint64Var(&options.seed, "seed", 0, "random seed; 0 picks one at random")
+	fs.Float64Var(&options.temperature, "temperature", 1,
+		"sharpens (<1) or flattens (>1) the next-token distribution; 0 always picks the likeliest")
+	fs.IntVar(&options.length, "length", 100, "maximum number of tokens to generate")
+	fs.StringVar(&options.prefix, "prefix", "", "text to start from; defaults to a random context")
+	return fs, options
+}
+
+// generate runs the generate mode with the command line args, printing the
+// generated text to out
+func generate(args []string, out io.Writer) error {
+	fs, options := generateFlags(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if options.temperature < 0 || math.IsNaN(options.temperature) {
+		return fmt.Errorf("temperature %v is negative", options.temperature)
+	}
+	if options.length < 0 {
+		return fmt.Errorf("length %d is negative", options.length)
+	}
+
+	var m *model
+	var err error
+	switch {
+	case options.load != "" && options.corpus != "":
+		return errors.New("give either -corpus or -model, not both")
+	case options.load != "":
+		m, err = loadModel(options.load)
+	case options.corpus != "":
+		m, err = buildModel(options.corpus, options.level, options.order)
+	default:
+		return errors.New("give -corpus or -model")
+	}
+	if err != nil {
+		return err
+	}
+	if options.save != "" {
+		if err := saveModel(m, options.save); err != nil {
+			return err
+		}
+	}
+
+	seed := options.seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	rng := rand.New(rand.NewPCG(seed, seed))
+	tokens, err := m.generate(options.prefix, options.length, options.temperature, rng)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, join(tokens, m.Level))
+	return err
+}
+
+// tokenize splits text into words or characters
+func tokenize(text, level string) ([]string, error) {
+	switch level {
+	case "word":
+		return strings.Fields(text), nil
+	case "char":
+		return strings.Split(text, ""), nil
+	default:
+		return nil, fmt.Errorf("unknown level %q: want word or char", level)
+	}
+}
+
+// join is the inverse of tokenize, up to whitespace between words
+func join(tokens []string, level string) string {
+	if level == "char" {
+		return strings.Join(tokens, "")
+	}
+	return strings.Join(tokens, " ")
+}
+
+// buildModel fits a chain of the given order to the corpus at path
+func buildModel(path, level string, order int) (*model, error) {
+	var text []byte
+	var err error
+	if path == "-" {
+		text, err = io.ReadAll(os.Stdin)
+	} else {
+		text, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := tokenize(string(text), level)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) <= order {
+		return nil, fmt.Errorf("corpus of %d tokens is too short for order %d", len(tokens), order)
+	}
+	chain, err := markov.FitHigherOrderLabels([][]string{tokens}, order, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &model{Level: level, Chain: chain}, nil
+}
+
+func loadModel(path string) (*model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &model{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("reading model %s: %w", path, err)
+	}
+	if m.Chain == nil || m.Chain.Labels == nil || m.Chain.NumContexts() == 0 {
+		return nil, fmt.Errorf("model %s has no labelled chain", path)
+	}
+	if _, err := tokenize("", m.Level); err != nil {
+		return nil, fmt.Errorf("model %s: %w", path, err)
+	}
+	return m, nil
+}
+
+func saveModel(m *model, path string) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// generate returns the tokens of prefix, or of a random observed context if
+// prefix is empty, followed by at most length generated tokens. It stops
+// early at a context that never occurs in the corpus.
+func (m *model) generate(prefix string, length int, temperature float64, rng *rand.Rand) ([]string, error) {
+	chain := m.Chain
+	var history []int
+	if prefix == "" {
+		contexts := chain.Contexts()
+		history = append(history, contexts[rng.IntN(len(contexts))]...)
+	} else {
+		index := make(map[string]int, len(chain.Labels))
+		for i, label := range chain.Labels {
+			index[label] = i
+		}
+		tokens, err := tokenize(prefix, m.Level)
+		if err != nil {
+			return nil, err
+		}
+		for _, token := range tokens {
+			s, ok := index[token]
+			if !ok {
+				return nil, fmt.Errorf("prefix token %q does not occur in the corpus", token)
+			}
+			history = append(history, s)
+		}
+		if len(history) < chain.Order {
+			return nil, fmt.Errorf(
+				"prefix of %d tokens is shorter than order %d", len(history), chain.Order)
+		}
+	}
+
+	for k := 0; k < length && chain.Observed(history); k++ {
+		dist, err := chain.Predict(history)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, sampleTemperature(dist, temperature, rng))
+	}
+
+	tokens := make([]string, len(history))
+	for i, s := range history {
+		tokens[i] = chain.Label(s)
+	}
+	return tokens, nil
+}
+
+// sampleTemperature draws an index with probability proportional to
+// dist[i]^(1/temperature), or the likeliest index if temperature is 0
+func sampleTemperature(dist []float64, temperature float64, rng *rand.Rand) int {
+	if temperature == 0 {
+		best := 0
+		for i, p := range dist {
+			if p > dist[best] {
+				best = i
+			}
+		}
+		return best
+	}
+
+	// Work in logs so that small temperatures do not underflow every weight
+	logs := make([]float64, len(dist))
+	top := math.Inf(-1)
+	for i, p := range dist {
+		logs[i] = math.Log(p) / temperature
+		top = math.Max(top, logs[i])
+	}
+	weights := make([]float64, len(dist))
+	total := 0.0
+	for i, l := range logs {
+		weights[i] = math.Exp(l - top)
+		total += weights[i]
+	}
+
+	u := rng.Float64() * total
+	last := 0
+	for i, w := range weights {
+		if w == 0 {
+			continue
+		}
+		last = i
+		if u < w {
+			return i
+		}
+		u -= w
+	}
+	return last
+}
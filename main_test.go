@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testCorpus = `the cat sat on the mat. the dog sat on the log.
+the cat saw the dog and the dog saw the cat.`
+
+// writeCorpus writes testCorpus to a temporary file and returns its path
+func writeCorpus(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "corpus.txt")
+	if err := os.WriteFile(path, []byte(testCorpus), 0o644); err != nil {
+		t.Fatalf("Failed to write corpus: %v", err)
+	}
+	return path
+}
+
+// TestSampleTemperature checks greedy, sharpened and plain sampling
+func TestSampleTemperature(t *testing.T) {
+	t.Parallel()
+	dist := []float64{0.2, 0, 0.5, 0.3}
+
+	tests := []struct {
+		name        string
+		temperature float64
+		want        []float64
+		tol         float64
+	}{
+		{"Greedy", 0, []float64{0, 0, 1, 0}, 0},
+		{"Low", 0.05, []float64{0, 0, 1, 0}, 0.001},
+		{"Plain", 1, dist, 0.01},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			rng := rand.New(rand.NewPCG(1, 2))
+			const draws = 100000
+			counts := make([]float64, len(dist))
+			for k := 0; k < draws; k++ {
+				counts[sampleTemperature(dist, test.temperature, rng)]++
+			}
+			if counts[1] > 0 {
+				t.Fatalf("Sampled index 1 with zero probability")
+			}
+			for i, want := range test.want {
+				if got := counts[i] / draws; got < want-test.tol || got > want+test.tol {
+					t.Fatalf("Index %d sampled with frequency %f, expected %f", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestGeneratePrefix checks generation from a prefix and prefix errors
+func TestGeneratePrefix(t *testing.T) {
+	t.Parallel()
+	corpus := writeCorpus(t)
+
+	var out bytes.Buffer
+	err := generate([]string{"-corpus", corpus, "-prefix", "the cat", "-seed", "3"}, &out)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "the cat ") {
+		t.Fatalf("Expected text starting with the prefix, got %q", out.String())
+	}
+
+	tests := []struct {
+		name   string
+		prefix string
+	}{
+		{"UnknownToken", "the zebra"},
+		{"TooShort", "the"},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			err := generate([]string{"-corpus", corpus, "-prefix", test.prefix}, &out)
+			if err == nil {
+				t.Fatalf("Expected error for prefix %q", test.prefix)
+			}
+		})
+	}
+}
+
+// TestGenerateSaveLoad checks that a saved model generates the same text as
+// the corpus it was built from, and that invalid model files are rejected
+func TestGenerateSaveLoad(t *testing.T) {
+	t.Parallel()
+	corpus := writeCorpus(t)
+	dir := t.TempDir()
+	modelPath := filepath.Join(dir, "model.json")
+
+	var built, loaded bytes.Buffer
+	err := generate([]string{
+		"-corpus", corpus, "-level", "char", "-order", "3",
+		"-seed", "5", "-length", "60", "-save", modelPath}, &built)
+	if err != nil {
+		t.Fatalf("generate with -save failed: %v", err)
+	}
+	err = generate([]string{"-model", modelPath, "-seed", "5", "-length", "60"}, &loaded)
+	if err != nil {
+		t.Fatalf("generate with -model failed: %v", err)
+	}
+	if built.String() != loaded.String() {
+		t.Fatalf("Saved model generated %q, expected %q", loaded.String(), built.String())
+	}
+
+	empty := filepath.Join(dir, "empty.json")
+	data := `{"level": "word", "chain": {"order": 1, "num_states": 1, "labels": ["a"], "contexts": []}}`
+	if err := os.WriteFile(empty, []byte(data), 0o644); err != nil {
+		t.Fatalf("Failed to write model: %v", err)
+	}
+	if err := generate([]string{"-model", empty}, &loaded); err == nil {
+		t.Fatalf("Expected error for model without contexts")
+	}
+}
@@ -0,0 +1,105 @@
+package markov
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pforderique/markov_chain/linalg"
+)
+
+// ErrNotAbsorbing is returned by Chain.Absorbing when the chain has no
+// absorbing state, or has a state that can never reach one.
+var ErrNotAbsorbing = errors.New("chain is not absorbing")
+
+// AbsorbingAnalysis describes a chain in the canonical form
+//
+//	P = [Q R]
+//	    [0 I]
+//
+// where Q holds the transitions among the t transient states and R the
+// transitions from transient to the r absorbing states. Rows of N, B,
+// ExpectedSteps and StepsVariance follow the order of Transient; columns of R
+// and B follow the order of Absorbing.
+type AbsorbingAnalysis struct {
+	Transient []int
+	Absorbing []int
+
+	Q *linalg.SquareMatrix
+	R *linalg.Matrix
+
+	// N = (I - Q)^-1 is the fundamental matrix: N[i][j] is the expected
+	// number of visits to transient state j starting from transient state i.
+	N *linalg.SquareMatrix
+	// B = NR: B[i][k] is the probability of being absorbed in absorbing state
+	// k starting from transient state i.
+	B *linalg.Matrix
+	// ExpectedSteps = N1 is the expected number of steps before absorption.
+	ExpectedSteps []float64
+	// StepsVariance = (2N - I)t - t∘t is the variance of the number of steps
+	// before absorption.
+	StepsVariance []float64
+}
+
+// Absorbing partitions c into transient and absorbing states, where a state
+// is absorbing if it moves to itself with probability 1, and computes the
+// standard absorbing chain quantities.
+func (c *Chain) Absorbing() (*AbsorbingAnalysis, error) {
+	a := &AbsorbingAnalysis{}
+	for i := 0; i < c.NumStates(); i++ {
+		if c.P.Get(i, i) == 1 {
+			a.Absorbing = append(a.Absorbing, i)
+		} else {
+			a.Transient = append(a.Transient, i)
+		}
+	}
+	if len(a.Absorbing) == 0 {
+		return nil, fmt.Errorf("%w: no state is absorbing", ErrNotAbsorbing)
+	}
+
+	a.Q = c.P.PrincipalSubMatrix(a.Transient)
+	a.R = c.P.SubMatrix(a.Transient, a.Absorbing)
+	t := len(a.Transient)
+	if t == 0 {
+		a.N, _ = linalg.NewSquareMatrix(0)
+		a.B = a.R
+		return a, nil
+	}
+
+	IminusQ, err := linalg.Identity(t)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < t; i++ {
+		for j := 0; j < t; j++ {
+			IminusQ.Set(i, j, IminusQ.Get(i, j)-a.Q.Get(i, j))
+		}
+	}
+	a.N, err = IminusQ.Inverse()
+	if err != nil {
+		var singular *linalg.ErrSingular
+		if errors.As(err, &singular) {
+			return nil, fmt.Errorf(
+				"%w: some transient states cannot reach an absorbing state",
+				ErrNotAbsorbing)
+		}
+		return nil, err
+	}
+	a.B = a.N.ToMatrix().Multiply(a.R)
+
+	a.ExpectedSteps = make([]float64, t)
+	for i := 0; i < t; i++ {
+		for j := 0; j < t; j++ {
+			a.ExpectedSteps[i] += a.N.Get(i, j)
+		}
+	}
+
+	a.StepsVariance = make([]float64, t)
+	for i := 0; i < t; i++ {
+		v := -a.ExpectedSteps[i]
+		for j := 0; j < t; j++ {
+			v += 2 * a.N.Get(i, j) * a.ExpectedSteps[j]
+		}
+		a.StepsVariance[i] = v - a.ExpectedSteps[i]*a.ExpectedSteps[i]
+	}
+	return a, nil
+}
@@ -0,0 +1,68 @@
+package markov
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// TestAbsorbing runs the absorbing chain analysis on the drunkard's walk over
+// states 0..4 with absorbing ends
+func TestAbsorbing(t *testing.T) {
+	t.Parallel()
+	c := newTestChain(t, []float64{
+		1, 0, 0, 0, 0,
+		0.5, 0, 0.5, 0, 0,
+		0, 0.5, 0, 0.5, 0,
+		0, 0, 0.5, 0, 0.5,
+		0, 0, 0, 0, 1,
+	}, 5)
+
+	a, err := c.Absorbing()
+	if err != nil {
+		t.Fatalf("Absorbing failed: %v", err)
+	}
+	if !reflect.DeepEqual(a.Transient, []int{1, 2, 3}) ||
+		!reflect.DeepEqual(a.Absorbing, []int{0, 4}) {
+		t.Fatalf("Expected transient [1 2 3] and absorbing [0 4], got %v and %v",
+			a.Transient, a.Absorbing)
+	}
+
+	wantN := []float64{1.5, 1, 0.5, 1, 2, 1, 0.5, 1, 1.5}
+	wantB := []float64{0.75, 0.25, 0.5, 0.5, 0.25, 0.75}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if math.Abs(a.N.Get(i, j)-wantN[i*3+j]) > 1e-12 {
+				t.Fatalf("N[%d][%d] = %f, expected %f", i, j, a.N.Get(i, j), wantN[i*3+j])
+			}
+		}
+		for k := 0; k < 2; k++ {
+			if math.Abs(a.B.Get(i, k)-wantB[i*2+k]) > 1e-12 {
+				t.Fatalf("B[%d][%d] = %f, expected %f", i, k, a.B.Get(i, k), wantB[i*2+k])
+			}
+		}
+	}
+	if !almostEqual(a.ExpectedSteps, []float64{3, 4, 3}, 1e-12) {
+		t.Fatalf("Expected steps [3 4 3], got %v", a.ExpectedSteps)
+	}
+	if !almostEqual(a.StepsVariance, []float64{8, 8, 8}, 1e-12) {
+		t.Fatalf("Expected variances [8 8 8], got %v", a.StepsVariance)
+	}
+}
+
+// TestAbsorbingErrors checks chains that are not absorbing
+func TestAbsorbingErrors(t *testing.T) {
+	t.Parallel()
+
+	noAbsorbing := newTestChain(t, []float64{0.5, 0.5, 0.5, 0.5}, 2)
+	if _, err := noAbsorbing.Absorbing(); !errors.Is(err, ErrNotAbsorbing) {
+		t.Fatalf("Expected ErrNotAbsorbing, got %v", err)
+	}
+
+	// States 1 and 2 swap forever and never reach state 0
+	trapped := newTestChain(t, []float64{1, 0, 0, 0, 0, 1, 0, 1, 0}, 3)
+	if _, err := trapped.Absorbing(); !errors.Is(err, ErrNotAbsorbing) {
+		t.Fatalf("Expected ErrNotAbsorbing, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package markov
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pforderique/markov_chain/linalg"
+)
+
+// Tolerance is how far a distribution, such as a row of a transition matrix,
+// may sum away from 1.
+const Tolerance = 1e-9
+
+// Chain is a discrete-time Markov chain over n states. P.Get(i, j) is the
+// probability of moving from state i to state j in one step.
+type Chain struct {
+	P      *linalg.SquareMatrix
+	Labels []string
+}
+
+// NewChain validates P as a row-stochastic matrix and returns a Chain over it.
+// labels may be nil; otherwise it must name every state.
+func NewChain(P *linalg.SquareMatrix, labels []string) (*Chain, error) {
+	if P == nil {
+		return nil, fmt.Errorf("transition matrix is nil")
+	}
+	if labels != nil && len(labels) != P.N() {
+		return nil, fmt.Errorf(
+			"%d labels given for a chain with %d states", len(labels), P.N())
+	}
+	if err := ValidateStochastic(P); err != nil {
+		return nil, err
+	}
+	return &Chain{P, labels}, nil
+}
+
+// ValidateStochastic checks that every row of P is non-negative and sums to
+// 1 within Tolerance.
+func ValidateStochastic(P *linalg.SquareMatrix) error {
+	row := make([]float64, P.N())
+	for i := 0; i < P.N(); i++ {
+		for j := range row {
+			row[j] = P.Get(i, j)
+		}
+		if err := ValidateDistribution(fmt.Sprintf("row %d of transition matrix", i), row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateDistribution checks that dist is non-negative and sums to 1 within
+// Tolerance. name describes dist in the returned error.
+func ValidateDistribution(name string, dist []float64) error {
+	sum := 0.0
+	for i, p := range dist {
+		if p < 0 || math.IsNaN(p) {
+			return fmt.Errorf("entry %d of %s = %v is not non-negative", i, name, p)
+		}
+		sum += p
+	}
+	if math.Abs(sum-1) > Tolerance {
+		return fmt.Errorf("%s sums to %v, not 1", name, sum)
+	}
+	return nil
+}
+
+// NumStates returns the number of states in c.
+func (c *Chain) NumStates() int {
+	return c.P.N()
+}
+
+// Label returns the label of state i, or its index if c has no labels.
+func (c *Chain) Label(i int) string {
+	if c.Labels == nil {
+		return fmt.Sprint(i)
+	}
+	return c.Labels[i]
+}
+
+// Step returns the distribution after one step starting from dist.
+func (c *Chain) Step(dist []float64) ([]float64, error) {
+	if len(dist) != c.NumStates() {
+		return nil, fmt.Errorf(
+			"distribution of length %d does not match chain with %d states",
+			len(dist), c.NumStates())
+	}
+	return c.P.LeftMultiply(dist), nil
+}
+
+// StepN returns the distribution after k steps starting from dist.
+func (c *Chain) StepN(dist []float64, k int) ([]float64, error) {
+	if k < 0 {
+		return nil, fmt.Errorf("cannot take a negative number of steps %d", k)
+	}
+	if len(dist) != c.NumStates() {
+		return nil, fmt.Errorf(
+			"distribution of length %d does not match chain with %d states",
+			len(dist), c.NumStates())
+	}
+
+	result := append([]float64(nil), dist...)
+	for i := 0; i < k; i++ {
+		result = c.P.LeftMultiply(result)
+	}
+	return result, nil
+}
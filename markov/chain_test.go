@@ -0,0 +1,123 @@
+package markov
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pforderique/markov_chain/linalg"
+)
+
+func newTestChain(t *testing.T, data []float64, n int) *Chain {
+	t.Helper()
+	P, err := linalg.NewSquareMatrixFromData(data, n)
+	if err != nil {
+		t.Fatalf("Failed to create transition matrix: %v", err)
+	}
+	c, err := NewChain(P, nil)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	return c
+}
+
+func almostEqual(a, b []float64, tol float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > tol {
+			return false
+		}
+	}
+	return true
+}
+
+// TestNewChain calls NewChain with valid and invalid transition matrices
+func TestNewChain(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    []float64
+		n       int
+		labels  []string
+		wantErr bool
+	}{
+		{"Valid", []float64{0.5, 0.5, 0.1, 0.9}, 2, nil, false},
+		{"Labelled", []float64{0.5, 0.5, 0.1, 0.9}, 2, []string{"a", "b"}, false},
+		{"RowSum", []float64{0.5, 0.4, 0.1, 0.9}, 2, nil, true},
+		{"Negative", []float64{1.5, -0.5, 0.1, 0.9}, 2, nil, true},
+		{"Labels", []float64{0.5, 0.5, 0.1, 0.9}, 2, []string{"a"}, true},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			P, err := linalg.NewSquareMatrixFromData(test.data, test.n)
+			if err != nil {
+				t.Fatalf("Failed to create transition matrix: %v", err)
+			}
+			_, err = NewChain(P, test.labels)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Expected error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestValidateDistribution calls ValidateDistribution with valid and invalid
+// distributions
+func TestValidateDistribution(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		dist    []float64
+		wantErr bool
+	}{
+		{"Valid", []float64{0.25, 0.75}, false},
+		{"WithinTolerance", []float64{0.5, 0.5 + Tolerance/2}, false},
+		{"Sum", []float64{0.5, 0.5 + 2*Tolerance}, true},
+		{"Negative", []float64{1.5, -0.5}, true},
+		{"NaN", []float64{math.NaN(), 1}, true},
+		{"Empty", nil, true},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateDistribution("test distribution", test.dist)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Expected error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestStepN calls Chain.Step and Chain.StepN
+func TestStepN(t *testing.T) {
+	t.Parallel()
+	c := newTestChain(t, []float64{0.9, 0.1, 0.5, 0.5}, 2)
+
+	one, err := c.Step([]float64{1, 0})
+	if err != nil {
+		t.Fatalf("Step failed: %v", err)
+	}
+	if !almostEqual(one, []float64{0.9, 0.1}, 1e-12) {
+		t.Fatalf("Expected [0.9 0.1], got %v", one)
+	}
+
+	two, err := c.StepN([]float64{1, 0}, 2)
+	if err != nil {
+		t.Fatalf("StepN failed: %v", err)
+	}
+	if !almostEqual(two, []float64{0.86, 0.14}, 1e-12) {
+		t.Fatalf("Expected [0.86 0.14], got %v", two)
+	}
+
+	if _, err := c.Step([]float64{1}); err == nil {
+		t.Fatalf("Expected error for mismatched distribution length")
+	}
+}
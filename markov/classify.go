@@ -0,0 +1,183 @@
+package markov
+
+// Class is a communicating class: a maximal set of states that can all reach
+// each other.
+type Class struct {
+	States []int
+	// Closed reports whether no transition leaves the class. In a finite
+	// chain a class is recurrent exactly when it is closed; states in other
+	// classes are transient.
+	Closed bool
+	// Period is the gcd of the lengths of all cycles through the class, or 0
+	// for a single state without a self-loop, which has no cycles.
+	Period int
+}
+
+// Recurrent reports whether the chain returns to the class with probability 1
+// once it is there.
+func (k Class) Recurrent() bool {
+	return k.Closed
+}
+
+// Classification is the breakdown of a chain into communicating classes.
+type Classification struct {
+	Classes []Class
+	// ClassOf[i] is the index in Classes of the class containing state i.
+	ClassOf []int
+	// Irreducible reports whether every state can reach every other state.
+	Irreducible bool
+	// Aperiodic reports whether every recurrent class has period 1.
+	Aperiodic bool
+}
+
+// Ergodic reports whether the chain is irreducible and aperiodic, so that it
+// converges to a unique stationary distribution from any start.
+func (cl *Classification) Ergodic() bool {
+	return cl.Irreducible && cl.Aperiodic
+}
+
+// RecurrentClasses returns the closed classes of the chain.
+func (cl *Classification) RecurrentClasses() []Class {
+	var closed []Class
+	for _, k := range cl.Classes {
+		if k.Closed {
+			closed = append(closed, k)
+		}
+	}
+	return closed
+}
+
+// Classify finds the communicating classes of c with Tarjan's algorithm over
+// the non-zero transitions, and determines which are closed and their
+// periods. Classes are listed in reverse topological order, so every class
+// can only be left towards classes listed before it.
+func (c *Chain) Classify() *Classification {
+	n := c.NumStates()
+	adjacency := make([][]int, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if c.P.Get(i, j) > 0 {
+				adjacency[i] = append(adjacency[i], j)
+			}
+		}
+	}
+
+	cl := &Classification{ClassOf: make([]int, n)}
+	for _, states := range stronglyConnectedComponents(adjacency) {
+		for _, s := range states {
+			cl.ClassOf[s] = len(cl.Classes)
+		}
+		cl.Classes = append(cl.Classes, Class{States: states})
+	}
+
+	cl.Aperiodic = true
+	for idx := range cl.Classes {
+		k := &cl.Classes[idx]
+		k.Closed = true
+		for _, s := range k.States {
+			for _, t := range adjacency[s] {
+				if cl.ClassOf[t] != idx {
+					k.Closed = false
+				}
+			}
+		}
+		k.Period = classPeriod(adjacency, cl.ClassOf, idx, k.States[0])
+		if k.Closed && k.Period != 1 {
+			cl.Aperiodic = false
+		}
+	}
+	cl.Irreducible = len(cl.Classes) == 1
+	return cl
+}
+
+// stronglyConnectedComponents runs Tarjan's algorithm over the graph with the
+// given adjacency lists
+func stronglyConnectedComponents(adjacency [][]int) [][]int {
+	n := len(adjacency)
+	index := make([]int, n)
+	lowlink := make([]int, n)
+	onStack := make([]bool, n)
+	for i := range index {
+		index[i] = -1
+	}
+	var stack []int
+	var components [][]int
+	counter := 0
+
+	var visit func(v int)
+	visit = func(v int) {
+		index[v] = counter
+		lowlink[v] = counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range adjacency[v] {
+			if index[w] == -1 {
+				visit(w)
+				lowlink[v] = min(lowlink[v], lowlink[w])
+			} else if onStack[w] {
+				lowlink[v] = min(lowlink[v], index[w])
+			}
+		}
+
+		if lowlink[v] == index[v] {
+			var component []int
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component = append(component, w)
+				if w == v {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for v := 0; v < n; v++ {
+		if index[v] == -1 {
+			visit(v)
+		}
+	}
+	return components
+}
+
+// classPeriod returns the period of the class numbered class by labelling
+// its states with BFS levels from root and taking the gcd of
+// level(u) + 1 - level(v) over all edges u -> v inside the class
+func classPeriod(adjacency [][]int, classOf []int, class, root int) int {
+	level := map[int]int{root: 0}
+	queue := []int{root}
+	period := 0
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, v := range adjacency[u] {
+			if classOf[v] != class {
+				continue
+			}
+			if lv, seen := level[v]; seen {
+				period = gcd(period, level[u]+1-lv)
+			} else {
+				level[v] = level[u] + 1
+				queue = append(queue, v)
+			}
+		}
+	}
+	return period
+}
+
+func gcd(a, b int) int {
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,103 @@
+package markov
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// TestClassify calls Chain.Classify on chains with known structure
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		data          []float64
+		n             int
+		wantClasses   [][]int
+		wantClosed    []bool
+		wantPeriods   []int
+		wantErgodic   bool
+		wantAperiodic bool
+	}{
+		{
+			"Ergodic",
+			[]float64{0.5, 0.5, 0.2, 0.8},
+			2,
+			[][]int{{0, 1}},
+			[]bool{true},
+			[]int{1},
+			true, true,
+		},
+		{
+			"Periodic",
+			[]float64{0, 1, 0, 0, 0, 1, 1, 0, 0},
+			3,
+			[][]int{{0, 1, 2}},
+			[]bool{true},
+			[]int{3},
+			false, false,
+		},
+		{
+			"BipartiteWalk",
+			[]float64{0, 1, 0, 0, 0.5, 0, 0.5, 0, 0, 0.5, 0, 0.5, 0, 0, 1, 0},
+			4,
+			[][]int{{0, 1, 2, 3}},
+			[]bool{true},
+			[]int{2},
+			false, false,
+		},
+		{
+			// 0 -> {1, 2} transient, {1, 2} closed periodic, 3 absorbing
+			"Reducible",
+			[]float64{
+				0, 0.5, 0, 0.5,
+				0, 0, 1, 0,
+				0, 1, 0, 0,
+				0, 0, 0, 1,
+			},
+			4,
+			[][]int{{1, 2}, {3}, {0}},
+			[]bool{true, true, false},
+			[]int{2, 1, 0},
+			false, false,
+		},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			c := newTestChain(t, test.data, test.n)
+			cl := c.Classify()
+			if len(cl.Classes) != len(test.wantClasses) {
+				t.Fatalf("Expected %d classes, got %+v", len(test.wantClasses), cl.Classes)
+			}
+			for i, k := range cl.Classes {
+				states := slices.Clone(k.States)
+				slices.Sort(states)
+				if !reflect.DeepEqual(states, test.wantClasses[i]) {
+					t.Fatalf("Class %d: expected states %v, got %v",
+						i, test.wantClasses[i], states)
+				}
+				if k.Closed != test.wantClosed[i] || k.Recurrent() != test.wantClosed[i] {
+					t.Fatalf("Class %d: expected closed %v, got %v",
+						i, test.wantClosed[i], k.Closed)
+				}
+				if k.Period != test.wantPeriods[i] {
+					t.Fatalf("Class %d: expected period %d, got %d",
+						i, test.wantPeriods[i], k.Period)
+				}
+				for _, s := range k.States {
+					if cl.ClassOf[s] != i {
+						t.Fatalf("ClassOf[%d] = %d, expected %d", s, cl.ClassOf[s], i)
+					}
+				}
+			}
+			if cl.Ergodic() != test.wantErgodic || cl.Aperiodic != test.wantAperiodic {
+				t.Fatalf("Expected ergodic %v and aperiodic %v, got %v and %v",
+					test.wantErgodic, test.wantAperiodic, cl.Ergodic(), cl.Aperiodic)
+			}
+		})
+	}
+}
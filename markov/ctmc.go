@@ -0,0 +1,237 @@
+package markov
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pforderique/markov_chain/linalg"
+)
+
+// CTMC is a continuous-time Markov chain over n states with generator matrix
+// Q: for i != j, Q.Get(i, j) is the rate of jumping from state i to state j,
+// and each diagonal entry makes its row sum to 0.
+type CTMC struct {
+	Q      *linalg.SquareMatrix
+	Labels []string
+}
+
+// TransientMethod selects the algorithm used by CTMC.Transient.
+type TransientMethod int
+
+const (
+	// Uniformization sums the Poisson-weighted steps of the uniformized chain.
+	// It only ever adds non-negative terms, so it is the more robust choice
+	// for stiff generators.
+	Uniformization TransientMethod = iota
+	// MatrixExponential computes e^{Qt} and multiplies by it.
+	MatrixExponential
+)
+
+// uniformizationSegment bounds the Poisson mean Λt handled in one go by
+// Transient, so that e^{-Λt} does not underflow
+const uniformizationSegment = 50
+
+// NewCTMC validates Q as a generator matrix and returns a CTMC over it.
+// labels may be nil; otherwise it must name every state.
+func NewCTMC(Q *linalg.SquareMatrix, labels []string) (*CTMC, error) {
+	if Q == nil {
+		return nil, fmt.Errorf("generator matrix is nil")
+	}
+	if labels != nil && len(labels) != Q.N() {
+		return nil, fmt.Errorf(
+			"%d labels given for a chain with %d states", len(labels), Q.N())
+	}
+
+	for i := 0; i < Q.N(); i++ {
+		sum, scale := 0.0, 1.0
+		for j := 0; j < Q.N(); j++ {
+			q := Q.Get(i, j)
+			if math.IsNaN(q) || math.IsInf(q, 0) || i != j && q < 0 {
+				return nil, fmt.Errorf(
+					"transition rate Q[%d][%d] = %v is not a non-negative number",
+					i, j, q)
+			}
+			sum += q
+			scale = math.Max(scale, math.Abs(q))
+		}
+		if math.Abs(sum) > Tolerance*scale {
+			return nil, fmt.Errorf("row %d of generator matrix sums to %v, not 0", i, sum)
+		}
+	}
+	return &CTMC{Q, labels}, nil
+}
+
+// NumStates returns the number of states in c.
+func (c *CTMC) NumStates() int {
+	return c.Q.N()
+}
+
+// Rates returns the rate -Q[i][i] at which c leaves each state i. The time
+// spent in state i is exponentially distributed with this rate.
+func (c *CTMC) Rates() []float64 {
+	rates := make([]float64, c.NumStates())
+	for i := range rates {
+		rates[i] = -c.Q.Get(i, i)
+	}
+	return rates
+}
+
+// JumpChain returns the embedded discrete-time chain of the states c visits,
+// ignoring how long it stays in each. States that c never leaves are
+// absorbing in the jump chain.
+func (c *CTMC) JumpChain() (*Chain, error) {
+	n := c.NumStates()
+	P, err := linalg.NewSquareMatrix(n)
+	if err != nil {
+		return nil, err
+	}
+	for i, rate := range c.Rates() {
+		if rate == 0 {
+			P.Set(i, i, 1)
+			continue
+		}
+		for j := 0; j < n; j++ {
+			if i != j {
+				P.Set(i, j, c.Q.Get(i, j)/rate)
+			}
+		}
+	}
+	return NewChain(P, c.Labels)
+}
+
+// uniformized returns the discrete chain P = I + Q/Λ along with Λ, the largest
+// exit rate. If c never moves, Λ is 0 and P is the identity. The diagonal is
+// set to 1 - Σ_{j≠i} P[i][j], so that rows sum to 1 even when the rows of Q
+// only sum to 0 within Tolerance relative to rates much smaller than 1.
+func (c *CTMC) uniformized() (*Chain, float64, error) {
+	n := c.NumStates()
+	lambda := 0.0
+	for i, rate := range c.Rates() {
+		out := 0.0
+		for j := 0; j < n; j++ {
+			if i != j {
+				out += c.Q.Get(i, j)
+			}
+		}
+		// Λ also covers the off-diagonal sums, which may exceed -Q[i][i]
+		// within Tolerance, so that the diagonal stays non-negative
+		lambda = math.Max(lambda, math.Max(rate, out))
+	}
+
+	P, err := linalg.Identity(n)
+	if err != nil {
+		return nil, 0, err
+	}
+	if lambda > 0 {
+		for i := 0; i < n; i++ {
+			stay := 1.0
+			for j := 0; j < n; j++ {
+				if i != j {
+					P.Set(i, j, c.Q.Get(i, j)/lambda)
+					stay -= P.Get(i, j)
+				}
+			}
+			P.Set(i, i, math.Max(stay, 0))
+		}
+	}
+	chain, err := NewChain(P, c.Labels)
+	return chain, lambda, err
+}
+
+// TransitionMatrix returns P(t) = e^{Qt}, whose entry (i, j) is the
+// probability of being in state j at time t after starting in state i.
+func (c *CTMC) TransitionMatrix(t float64) (*linalg.SquareMatrix, error) {
+	if t < 0 || math.IsNaN(t) {
+		return nil, fmt.Errorf("time %v is not non-negative", t)
+	}
+	n := c.NumStates()
+	Qt, err := linalg.NewSquareMatrix(n)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			Qt.Set(i, j, c.Q.Get(i, j)*t)
+		}
+	}
+	return Qt.Exp()
+}
+
+// Transient returns the distribution at time t of a chain started from dist.
+func (c *CTMC) Transient(dist []float64, t float64, method TransientMethod) ([]float64, error) {
+	if len(dist) != c.NumStates() {
+		return nil, fmt.Errorf(
+			"distribution of length %d does not match chain with %d states",
+			len(dist), c.NumStates())
+	}
+	if t < 0 || math.IsNaN(t) {
+		return nil, fmt.Errorf("time %v is not non-negative", t)
+	}
+
+	switch method {
+	case Uniformization:
+		return c.transientUniformization(dist, t)
+	case MatrixExponential:
+		P, err := c.TransitionMatrix(t)
+		if err != nil {
+			return nil, err
+		}
+		return P.LeftMultiply(dist), nil
+	default:
+		return nil, fmt.Errorf("unknown transient method %d", method)
+	}
+}
+
+// transientUniformization computes Σ_k Poisson(k; Λt)·dist·P^k for the
+// uniformized chain P, splitting t into segments with Λt at most
+// uniformizationSegment
+func (c *CTMC) transientUniformization(dist []float64, t float64) ([]float64, error) {
+	chain, lambda, err := c.uniformized()
+	if err != nil {
+		return nil, err
+	}
+	result := append([]float64(nil), dist...)
+	if lambda == 0 || t == 0 {
+		return result, nil
+	}
+
+	segments := int(math.Ceil(lambda * t / uniformizationSegment))
+	mean := lambda * t / float64(segments)
+	// Terms beyond this many standard deviations are negligible
+	maxTerms := int(mean+10*math.Sqrt(mean)) + 20
+
+	for ; segments > 0; segments-- {
+		term := result
+		weight := math.Exp(-mean)
+		cumulative := weight
+		next := make([]float64, len(result))
+		for i := range next {
+			next[i] = weight * term[i]
+		}
+		for k := 1; k <= maxTerms && cumulative < 1-1e-15; k++ {
+			term, _ = chain.Step(term)
+			weight *= mean / float64(k)
+			cumulative += weight
+			for i := range next {
+				next[i] += weight * term[i]
+			}
+		}
+		result = next
+	}
+	return result, nil
+}
+
+// Stationary returns the stationary distribution π of c, with πQ = 0. It is
+// the stationary distribution of the uniformized chain, and likewise returns
+// ErrReducible if it is not unique.
+func (c *CTMC) Stationary() ([]float64, error) {
+	chain, _, err := c.uniformized()
+	if err != nil {
+		return nil, err
+	}
+	result, err := chain.Stationary(&StationaryOptions{Method: DirectSolve})
+	if err != nil {
+		return nil, err
+	}
+	return result.Pi, nil
+}
@@ -0,0 +1,147 @@
+package markov
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/pforderique/markov_chain/linalg"
+)
+
+func newTestCTMC(t *testing.T, rows [][]float64) *CTMC {
+	t.Helper()
+	Q, err := linalg.FromRows(rows)
+	if err != nil {
+		t.Fatalf("Failed to create generator matrix: %v", err)
+	}
+	c, err := NewCTMC(Q, nil)
+	if err != nil {
+		t.Fatalf("Failed to create CTMC: %v", err)
+	}
+	return c
+}
+
+// TestNewCTMC calls NewCTMC with valid and invalid generator matrices
+func TestNewCTMC(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rows    [][]float64
+		wantErr bool
+	}{
+		{"Valid", [][]float64{{-2, 2}, {1, -1}}, false},
+		{"Absorbing", [][]float64{{-2, 2}, {0, 0}}, false},
+		{"RowSum", [][]float64{{-2, 1}, {1, -1}}, true},
+		{"NegativeRate", [][]float64{{1, -1}, {1, -1}}, true},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			Q, err := linalg.FromRows(test.rows)
+			if err != nil {
+				t.Fatalf("Failed to create generator matrix: %v", err)
+			}
+			_, err = NewCTMC(Q, nil)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Expected error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestCTMCTransient compares both transient methods against the closed form
+// for a two-state chain
+func TestCTMCTransient(t *testing.T) {
+	t.Parallel()
+	a, b := 3.0, 1.0
+	c := newTestCTMC(t, [][]float64{{-a, a}, {b, -b}})
+
+	for _, time := range []float64{0, 0.1, 1, 40} {
+		p0 := b/(a+b) + a/(a+b)*math.Exp(-(a+b)*time)
+		want := []float64{p0, 1 - p0}
+		for _, method := range []TransientMethod{Uniformization, MatrixExponential} {
+			result, err := c.Transient([]float64{1, 0}, time, method)
+			if err != nil {
+				t.Fatalf("Method %d failed: %v", method, err)
+			}
+			if !almostEqual(result, want, 1e-10) {
+				t.Fatalf("Method %d at t=%v: expected %v, got %v",
+					method, time, want, result)
+			}
+		}
+	}
+
+	if _, err := c.Transient([]float64{1, 0}, -1, Uniformization); err == nil {
+		t.Fatalf("Expected error for negative time")
+	}
+}
+
+// TestCTMCUniformizedSmallRates checks that uniformization accepts a generator
+// with rates much smaller than 1 whose rows sum to 0 only within Tolerance
+func TestCTMCUniformizedSmallRates(t *testing.T) {
+	t.Parallel()
+	c := newTestCTMC(t, [][]float64{{-1e-3, 1e-3 + 5e-10}, {2e-3, -2e-3}})
+
+	want, err := c.Transient([]float64{1, 0}, 100, MatrixExponential)
+	if err != nil {
+		t.Fatalf("MatrixExponential failed: %v", err)
+	}
+	got, err := c.Transient([]float64{1, 0}, 100, Uniformization)
+	if err != nil {
+		t.Fatalf("Uniformization failed: %v", err)
+	}
+	if !almostEqual(got, want, 1e-6) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestCTMCStationary calls CTMC.Stationary, CTMC.Rates and CTMC.JumpChain
+func TestCTMCStationary(t *testing.T) {
+	t.Parallel()
+	c := newTestCTMC(t, [][]float64{
+		{-3, 2, 1},
+		{1, -1, 0},
+		{4, 4, -8},
+	})
+
+	pi, err := c.Stationary()
+	if err != nil {
+		t.Fatalf("Stationary failed: %v", err)
+	}
+	// πQ = 0
+	for j := 0; j < 3; j++ {
+		sum := 0.0
+		for i := 0; i < 3; i++ {
+			sum += pi[i] * c.Q.Get(i, j)
+		}
+		if math.Abs(sum) > 1e-12 {
+			t.Fatalf("(πQ)[%d] = %g, expected 0", j, sum)
+		}
+	}
+
+	if rates := c.Rates(); !almostEqual(rates, []float64{3, 1, 8}, 0) {
+		t.Fatalf("Expected rates [3 1 8], got %v", rates)
+	}
+
+	jump, err := c.JumpChain()
+	if err != nil {
+		t.Fatalf("JumpChain failed: %v", err)
+	}
+	want := []float64{0, 2.0 / 3, 1.0 / 3, 1, 0, 0, 0.5, 0.5, 0}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if math.Abs(jump.P.Get(i, j)-want[i*3+j]) > 1e-12 {
+				t.Fatalf("Jump chain P[%d][%d] = %f, expected %f",
+					i, j, jump.P.Get(i, j), want[i*3+j])
+			}
+		}
+	}
+
+	absorbing := newTestCTMC(t, [][]float64{{0, 0}, {0, 0}})
+	if _, err := absorbing.Stationary(); !errors.Is(err, ErrReducible) {
+		t.Fatalf("Expected ErrReducible, got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package markov
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pforderique/markov_chain/linalg"
+)
+
+// UnseenPolicy decides the transitions of states that are never left in the
+// observed sequences and get no smoothing.
+type UnseenPolicy int
+
+const (
+	// UnseenSelfLoop makes unseen states absorbing.
+	UnseenSelfLoop UnseenPolicy = iota
+	// UnseenUniform moves from unseen states to every state with equal
+	// probability.
+	UnseenUniform
+)
+
+// FitOptions configures Fit and FitLabels. The zero value gives the
+// unsmoothed maximum-likelihood estimate with 95% confidence intervals.
+type FitOptions struct {
+	// NumStates is the number of states for Fit. Defaults to one more than the
+	// largest state observed.
+	NumStates int
+	// Labels fixes the states for FitLabels, in order. Defaults to the labels
+	// observed, in order of first appearance.
+	Labels []string
+	// Smoothing is the Dirichlet pseudo-count added to every transition
+	// count. 1 gives Laplace smoothing; 0 gives the maximum-likelihood
+	// estimate.
+	Smoothing float64
+	// Confidence is the level of the confidence intervals. Defaults to 0.95.
+	Confidence float64
+	// Unseen decides the rows of states with no observed or pseudo counts.
+	Unseen UnseenPolicy
+}
+
+// FitResult is a chain estimated from observed sequences.
+type FitResult struct {
+	Chain *Chain
+	// Counts.Get(i, j) is the number of observed transitions from i to j.
+	Counts *linalg.SquareMatrix
+	// RowTotals[i] is the number of observed transitions out of state i.
+	RowTotals []float64
+	// Lower and Upper bound the Wilson score confidence interval of each
+	// transition probability, computed from the observed counts. Rows with
+	// no observations get the interval [0, 1].
+	Lower, Upper *linalg.SquareMatrix
+	// Unseen lists the states never left in the sequences.
+	Unseen []int
+}
+
+// Fit estimates a chain from sequences of states numbered from 0. Each
+// sequence contributes its consecutive pairs as transitions; sequences are
+// not joined to each other.
+func Fit(sequences [][]int, opts *FitOptions) (*FitResult, error) {
+	if opts == nil {
+		opts = &FitOptions{}
+	}
+	n := opts.NumStates
+	for _, seq := range sequences {
+		for _, s := range seq {
+			if s < 0 {
+				return nil, fmt.Errorf("state %d is negative", s)
+			}
+			if opts.NumStates == 0 {
+				n = max(n, s+1)
+			} else if s >= opts.NumStates {
+				return nil, fmt.Errorf(
+					"state %d is out of range for %d states", s, opts.NumStates)
+			}
+		}
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("no states observed")
+	}
+
+	counts, err := linalg.NewSquareMatrix(n)
+	if err != nil {
+		return nil, err
+	}
+	for _, seq := range sequences {
+		for k := 1; k < len(seq); k++ {
+			counts.Set(seq[k-1], seq[k], counts.Get(seq[k-1], seq[k])+1)
+		}
+	}
+	return estimate(counts, opts)
+}
+
+// FitLabels estimates a chain from sequences of state labels, as Fit does,
+// and labels the states of the resulting chain.
+func FitLabels(sequences [][]string, opts *FitOptions) (*FitResult, error) {
+	if opts == nil {
+		opts = &FitOptions{}
+	}
+	indexed, labels, err := indexLabels(sequences, opts.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	intOpts := *opts
+	intOpts.NumStates = len(labels)
+	result, err := Fit(indexed, &intOpts)
+	if err != nil {
+		return nil, err
+	}
+	result.Chain.Labels = labels
+	return result, nil
+}
+
+// indexLabels numbers the labels in sequences by their position in given,
+// or if given is nil by order of first appearance, and returns the numbered
+// sequences along with the labels of each number
+func indexLabels(sequences [][]string, given []string) ([][]int, []string, error) {
+	labels := append([]string(nil), given...)
+	index := make(map[string]int, len(labels))
+	for i, label := range labels {
+		if _, ok := index[label]; ok {
+			return nil, nil, fmt.Errorf("label %q is given twice", label)
+		}
+		index[label] = i
+	}
+
+	indexed := make([][]int, len(sequences))
+	for k, seq := range sequences {
+		indexed[k] = make([]int, len(seq))
+		for i, label := range seq {
+			s, ok := index[label]
+			if !ok {
+				if given != nil {
+					return nil, nil, fmt.Errorf("label %q is not among the given labels", label)
+				}
+				s = len(labels)
+				index[label] = s
+				labels = append(labels, label)
+			}
+			indexed[k][i] = s
+		}
+	}
+	return indexed, labels, nil
+}
+
+// estimate builds the FitResult for the transition counts
+func estimate(counts *linalg.SquareMatrix, opts *FitOptions) (*FitResult, error) {
+	if opts.Smoothing < 0 || math.IsNaN(opts.Smoothing) {
+		return nil, fmt.Errorf("smoothing %v is negative", opts.Smoothing)
+	}
+	confidence := opts.Confidence
+	if confidence == 0 {
+		confidence = 0.95
+	}
+	if confidence <= 0 || confidence >= 1 {
+		return nil, fmt.Errorf("confidence level %v is not between 0 and 1", confidence)
+	}
+	z := math.Sqrt2 * math.Erfinv(confidence)
+
+	n := counts.N()
+	result := &FitResult{Counts: counts, RowTotals: make([]float64, n)}
+	P, err := linalg.NewSquareMatrix(n)
+	if err != nil {
+		return nil, err
+	}
+	if result.Lower, err = linalg.NewSquareMatrix(n); err != nil {
+		return nil, err
+	}
+	if result.Upper, err = linalg.NewSquareMatrix(n); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < n; i++ {
+		total := 0.0
+		for j := 0; j < n; j++ {
+			total += counts.Get(i, j)
+		}
+		result.RowTotals[i] = total
+
+		if total == 0 {
+			result.Unseen = append(result.Unseen, i)
+			for j := 0; j < n; j++ {
+				result.Upper.Set(i, j, 1)
+			}
+		} else {
+			for j := 0; j < n; j++ {
+				lower, upper := wilsonInterval(counts.Get(i, j), total, z)
+				result.Lower.Set(i, j, lower)
+				result.Upper.Set(i, j, upper)
+			}
+		}
+
+		smoothed := total + opts.Smoothing*float64(n)
+		switch {
+		case smoothed > 0:
+			for j := 0; j < n; j++ {
+				P.Set(i, j, (counts.Get(i, j)+opts.Smoothing)/smoothed)
+			}
+		case opts.Unseen == UnseenSelfLoop:
+			P.Set(i, i, 1)
+		case opts.Unseen == UnseenUniform:
+			for j := 0; j < n; j++ {
+				P.Set(i, j, 1/float64(n))
+			}
+		default:
+			return nil, fmt.Errorf("unknown unseen state policy %d", opts.Unseen)
+		}
+	}
+
+	result.Chain, err = NewChain(P, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// wilsonInterval returns the Wilson score interval for a proportion of
+// successes out of trials, for the normal quantile z
+func wilsonInterval(successes, trials, z float64) (float64, float64) {
+	p := successes / trials
+	z2 := z * z
+	denominator := 1 + z2/trials
+	center := (p + z2/(2*trials)) / denominator
+	margin := z / denominator * math.Sqrt(p*(1-p)/trials+z2/(4*trials*trials))
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
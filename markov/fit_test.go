@@ -0,0 +1,156 @@
+package markov
+
+import (
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+// TestFit checks the estimated transition matrix for several options
+func TestFit(t *testing.T) {
+	t.Parallel()
+	sequences := [][]int{{0, 1, 1, 0, 1}, {1, 0, 0}}
+
+	tests := []struct {
+		name       string
+		opts       *FitOptions
+		want       []float64
+		wantUnseen []int
+	}{
+		{
+			"MaximumLikelihood",
+			nil,
+			[]float64{1.0 / 3, 2.0 / 3, 2.0 / 3, 1.0 / 3},
+			nil,
+		},
+		{
+			"Laplace",
+			&FitOptions{Smoothing: 1},
+			[]float64{2.0 / 5, 3.0 / 5, 3.0 / 5, 2.0 / 5},
+			nil,
+		},
+		{
+			"UnseenSelfLoop",
+			&FitOptions{NumStates: 3},
+			[]float64{1.0 / 3, 2.0 / 3, 0, 2.0 / 3, 1.0 / 3, 0, 0, 0, 1},
+			[]int{2},
+		},
+		{
+			"UnseenUniform",
+			&FitOptions{NumStates: 3, Unseen: UnseenUniform},
+			[]float64{1.0 / 3, 2.0 / 3, 0, 2.0 / 3, 1.0 / 3, 0, 1.0 / 3, 1.0 / 3, 1.0 / 3},
+			[]int{2},
+		},
+		{
+			"SmoothedUnseen",
+			&FitOptions{NumStates: 3, Smoothing: 0.5},
+			[]float64{
+				1.5 / 4.5, 2.5 / 4.5, 0.5 / 4.5,
+				2.5 / 4.5, 1.5 / 4.5, 0.5 / 4.5,
+				1.0 / 3, 1.0 / 3, 1.0 / 3,
+			},
+			[]int{2},
+		},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			result, err := Fit(sequences, test.opts)
+			if err != nil {
+				t.Fatalf("Fit failed: %v", err)
+			}
+			n := result.Chain.NumStates()
+			got := make([]float64, 0, n*n)
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					got = append(got, result.Chain.P.Get(i, j))
+				}
+			}
+			if !almostEqual(got, test.want, 1e-12) {
+				t.Fatalf("Expected P = %v, got %v", test.want, got)
+			}
+			if !reflect.DeepEqual(result.Unseen, test.wantUnseen) {
+				t.Fatalf("Expected unseen states %v, got %v", test.wantUnseen, result.Unseen)
+			}
+			if result.RowTotals[0] != 3 || result.RowTotals[1] != 3 {
+				t.Fatalf("Expected row totals 3, got %v", result.RowTotals)
+			}
+		})
+	}
+
+	if _, err := Fit([][]int{{0, -1}}, nil); err == nil {
+		t.Fatalf("Expected error for negative state")
+	}
+	if _, err := Fit([][]int{{0, 3}}, &FitOptions{NumStates: 2}); err == nil {
+		t.Fatalf("Expected error for out-of-range state")
+	}
+	if _, err := Fit(sequences, &FitOptions{Confidence: 1.5}); err == nil {
+		t.Fatalf("Expected error for invalid confidence level")
+	}
+}
+
+// TestFitLabels fits labelled sequences with and without given labels
+func TestFitLabels(t *testing.T) {
+	t.Parallel()
+	sequences := [][]string{{"sun", "rain", "rain", "sun"}, {"rain", "sun"}}
+
+	result, err := FitLabels(sequences, nil)
+	if err != nil {
+		t.Fatalf("FitLabels failed: %v", err)
+	}
+	if !reflect.DeepEqual(result.Chain.Labels, []string{"sun", "rain"}) {
+		t.Fatalf("Expected labels [sun rain], got %v", result.Chain.Labels)
+	}
+	if result.Chain.P.Get(0, 1) != 1 || result.Chain.P.Get(1, 0) != 2.0/3 {
+		t.Fatalf("Unexpected transition matrix %v", result.Chain.P)
+	}
+
+	result, err = FitLabels(sequences, &FitOptions{Labels: []string{"snow", "rain", "sun"}})
+	if err != nil {
+		t.Fatalf("FitLabels failed: %v", err)
+	}
+	if result.Chain.Label(2) != "sun" || !reflect.DeepEqual(result.Unseen, []int{0}) {
+		t.Fatalf("Expected sun as state 2 and snow unseen, got %v and %v",
+			result.Chain.Labels, result.Unseen)
+	}
+
+	if _, err := FitLabels(sequences, &FitOptions{Labels: []string{"sun"}}); err == nil {
+		t.Fatalf("Expected error for label not among the given labels")
+	}
+}
+
+// TestFitRecovers fits a long simulated trajectory and checks that the true
+// transition matrix lies within the confidence intervals
+func TestFitRecovers(t *testing.T) {
+	t.Parallel()
+	c := newTestChain(t, []float64{
+		0.5, 0.3, 0.2,
+		0.1, 0.8, 0.1,
+		0.3, 0.3, 0.4,
+	}, 3)
+	path, err := c.Simulate(0, 100000, rand.New(rand.NewPCG(5, 6)))
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+
+	result, err := Fit([][]int{path}, &FitOptions{Confidence: 0.999})
+	if err != nil {
+		t.Fatalf("Fit failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			p := c.P.Get(i, j)
+			lower, upper := result.Lower.Get(i, j), result.Upper.Get(i, j)
+			if p < lower || p > upper {
+				t.Fatalf("P[%d][%d] = %f is outside interval [%f, %f]", i, j, p, lower, upper)
+			}
+			if math.Abs(result.Chain.P.Get(i, j)-p) > 0.02 {
+				t.Fatalf("Estimated P[%d][%d] = %f, expected about %f",
+					i, j, result.Chain.P.Get(i, j), p)
+			}
+		}
+	}
+}
@@ -0,0 +1,459 @@
+package markov
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/pforderique/markov_chain/linalg"
+)
+
+// HigherOrderChain is a chain whose next state depends on the previous Order
+// states. It only stores the contexts, tuples of Order states, that were
+// observed when fitting, along with the states observed to follow them.
+type HigherOrderChain struct {
+	Order     int
+	NumStates int
+	Labels    []string
+	// Unseen decides what follows a context that was never observed.
+	Unseen UnseenPolicy
+
+	contexts map[int]*contextRow
+}
+
+// contextRow is the distribution of the state following one context, over
+// the states in next
+type contextRow struct {
+	next  []int
+	probs []float64
+	table aliasTable
+}
+
+// FitHigherOrder estimates a chain of the given order from sequences of
+// states numbered from 0. opts.NumStates, opts.Smoothing and opts.Unseen are
+// used as in Fit; with smoothing every observed context can be followed by
+// every state, without it only by the states observed after it.
+func FitHigherOrder(sequences [][]int, order int, opts *FitOptions) (*HigherOrderChain, error) {
+	if opts == nil {
+		opts = &FitOptions{}
+	}
+	if order < 1 {
+		return nil, fmt.Errorf("order %d is not positive", order)
+	}
+	if opts.Smoothing < 0 || math.IsNaN(opts.Smoothing) {
+		return nil, fmt.Errorf("smoothing %v is negative", opts.Smoothing)
+	}
+	if opts.Unseen != UnseenSelfLoop && opts.Unseen != UnseenUniform {
+		return nil, fmt.Errorf("unknown unseen state policy %d", opts.Unseen)
+	}
+
+	n := opts.NumStates
+	for _, seq := range sequences {
+		for _, s := range seq {
+			if s < 0 {
+				return nil, fmt.Errorf("state %d is negative", s)
+			}
+			if opts.NumStates == 0 {
+				n = max(n, s+1)
+			} else if s >= opts.NumStates {
+				return nil, fmt.Errorf(
+					"state %d is out of range for %d states", s, opts.NumStates)
+			}
+		}
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("no states observed")
+	}
+	if float64(order)*math.Log2(float64(n)) >= 62 {
+		return nil, fmt.Errorf("%d states of order %d have too many contexts", n, order)
+	}
+
+	h := &HigherOrderChain{
+		Order:     order,
+		NumStates: n,
+		Unseen:    opts.Unseen,
+		contexts:  map[int]*contextRow{},
+	}
+	counts := map[int]map[int]float64{}
+	for _, seq := range sequences {
+		for k := order; k < len(seq); k++ {
+			key := h.key(seq[k-order : k])
+			if counts[key] == nil {
+				counts[key] = map[int]float64{}
+			}
+			counts[key][seq[k]]++
+		}
+	}
+	if len(counts) == 0 {
+		return nil, fmt.Errorf("no sequence is longer than order %d", order)
+	}
+
+	for key, row := range counts {
+		r := &contextRow{}
+		if opts.Smoothing > 0 {
+			for s := 0; s < n; s++ {
+				r.next = append(r.next, s)
+			}
+		} else {
+			for s := range row {
+				r.next = append(r.next, s)
+			}
+			slices.Sort(r.next)
+		}
+		total := opts.Smoothing * float64(n)
+		for _, c := range row {
+			total += c
+		}
+		for _, s := range r.next {
+			r.probs = append(r.probs, (row[s]+opts.Smoothing)/total)
+		}
+		r.table = newAliasTable(r.probs)
+		h.contexts[key] = r
+	}
+	return h, nil
+}
+
+// FitHigherOrderLabels estimates a chain of the given order from sequences
+// of state labels, as FitHigherOrder does, numbering the labels as FitLabels
+// does.
+func FitHigherOrderLabels(sequences [][]string, order int, opts *FitOptions) (*HigherOrderChain, error) {
+	if opts == nil {
+		opts = &FitOptions{}
+	}
+	indexed, labels, err := indexLabels(sequences, opts.Labels)
+	if err != nil {
+		return nil, err
+	}
+	intOpts := *opts
+	intOpts.NumStates = len(labels)
+	h, err := FitHigherOrder(indexed, order, &intOpts)
+	if err != nil {
+		return nil, err
+	}
+	h.Labels = labels
+	return h, nil
+}
+
+// key packs a context of Order states into a single integer
+func (h *HigherOrderChain) key(context []int) int {
+	key := 0
+	for _, s := range context {
+		key = key*h.NumStates + s
+	}
+	return key
+}
+
+// unpack returns the context packed into key
+func (h *HigherOrderChain) unpack(key int) []int {
+	context := make([]int, h.Order)
+	for i := h.Order - 1; i >= 0; i-- {
+		context[i] = key % h.NumStates
+		key /= h.NumStates
+	}
+	return context
+}
+
+// NumContexts returns the number of contexts observed when fitting h.
+func (h *HigherOrderChain) NumContexts() int {
+	return len(h.contexts)
+}
+
+// Contexts returns the contexts observed when fitting h, in increasing
+// order.
+func (h *HigherOrderChain) Contexts() [][]int {
+	keys := make([]int, 0, len(h.contexts))
+	for key := range h.contexts {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	contexts := make([][]int, len(keys))
+	for i, key := range keys {
+		contexts[i] = h.unpack(key)
+	}
+	return contexts
+}
+
+// Observed reports whether the last Order states of history form a context
+// observed when fitting h. It is false for invalid histories.
+func (h *HigherOrderChain) Observed(history []int) bool {
+	context, err := h.checkContext(history)
+	if err != nil {
+		return false
+	}
+	_, ok := h.contexts[h.key(context)]
+	return ok
+}
+
+// Label returns the label of state i, or its index if h has no labels.
+func (h *HigherOrderChain) Label(i int) string {
+	if h.Labels == nil {
+		return fmt.Sprint(i)
+	}
+	return h.Labels[i]
+}
+
+// checkContext validates history, which must hold at least Order states,
+// and returns its last Order states
+func (h *HigherOrderChain) checkContext(history []int) ([]int, error) {
+	if len(history) < h.Order {
+		return nil, fmt.Errorf(
+			"history of %d states is shorter than order %d", len(history), h.Order)
+	}
+	context := history[len(history)-h.Order:]
+	for _, s := range context {
+		if s < 0 || s >= h.NumStates {
+			return nil, fmt.Errorf(
+				"state %d is out of range for %d states", s, h.NumStates)
+		}
+	}
+	return context, nil
+}
+
+// Predict returns the distribution of the state following history, which
+// depends on its last Order states.
+func (h *HigherOrderChain) Predict(history []int) ([]float64, error) {
+	context, err := h.checkContext(history)
+	if err != nil {
+		return nil, err
+	}
+	dist := make([]float64, h.NumStates)
+	row, ok := h.contexts[h.key(context)]
+	switch {
+	case ok:
+		for i, s := range row.next {
+			dist[s] = row.probs[i]
+		}
+	case h.Unseen == UnseenUniform:
+		for s := range dist {
+			dist[s] = 1 / float64(h.NumStates)
+		}
+	default:
+		dist[context[len(context)-1]] = 1
+	}
+	return dist, nil
+}
+
+// next draws the state following context, which must be valid
+func (h *HigherOrderChain) next(context []int, rng *rand.Rand) int {
+	if row, ok := h.contexts[h.key(context)]; ok {
+		return row.next[row.table.sample(rng)]
+	}
+	if h.Unseen == UnseenUniform {
+		return rng.IntN(h.NumStates)
+	}
+	return context[len(context)-1]
+}
+
+// Simulate extends history by steps states and returns the whole trajectory.
+// history must hold at least Order states. If rng is nil a randomly seeded
+// generator is used.
+func (h *HigherOrderChain) Simulate(history []int, steps int, rng *rand.Rand) ([]int, error) {
+	if _, err := h.checkContext(history); err != nil {
+		return nil, err
+	}
+	if steps < 0 {
+		return nil, fmt.Errorf("number of steps %d is negative", steps)
+	}
+	if rng == nil {
+		rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+
+	path := make([]int, len(history), len(history)+steps)
+	copy(path, history)
+	for k := 0; k < steps; k++ {
+		path = append(path, h.next(path[len(path)-h.Order:], rng))
+	}
+	return path, nil
+}
+
+// maxLiftedStates bounds the number of states of the dense chain built by
+// Lift
+const maxLiftedStates = 4096
+
+// Lift returns the first-order chain whose states are contexts of h, along
+// with the context of each of its states. A step from (s1, ..., sk) moves to
+// (s2, ..., sk, s) with the probability Predict gives s. The states are the
+// contexts observed when fitting h, in the order of Contexts, followed by the
+// unobserved contexts reachable from them under h.Unseen, so the lifted chain
+// agrees with Predict and Simulate. Lift returns an error if there are more
+// than 4096 of them, which UnseenUniform reaches quickly for large alphabets.
+func (h *HigherOrderChain) Lift() (*Chain, [][]int, error) {
+	contexts := h.Contexts()
+	if len(contexts) > maxLiftedStates {
+		return nil, nil, fmt.Errorf(
+			"%d observed contexts are too many to lift into a dense chain", len(contexts))
+	}
+	index := make(map[int]int, len(contexts))
+	for i, context := range contexts {
+		index[h.key(context)] = i
+	}
+
+	// Follow the contexts reachable from the observed ones, appending each
+	// new one to contexts, and record the transitions between them
+	type transition struct {
+		from, to int
+		p        float64
+	}
+	var transitions []transition
+	for i := 0; i < len(contexts); i++ {
+		dist, err := h.Predict(contexts[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		for s, p := range dist {
+			if p == 0 {
+				continue
+			}
+			next := append(slices.Clone(contexts[i][1:]), s)
+			key := h.key(next)
+			j, ok := index[key]
+			if !ok {
+				if len(contexts) == maxLiftedStates {
+					return nil, nil, fmt.Errorf(
+						"more than %d contexts are reachable from the observed ones",
+						maxLiftedStates)
+				}
+				j = len(contexts)
+				index[key] = j
+				contexts = append(contexts, next)
+			}
+			transitions = append(transitions, transition{i, j, p})
+		}
+	}
+
+	n := len(contexts)
+	P, err := linalg.NewSquareMatrix(n)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, t := range transitions {
+		P.Set(t.from, t.to, t.p)
+	}
+
+	var labels []string
+	if h.Labels != nil {
+		labels = make([]string, n)
+		for i, context := range contexts {
+			for k, s := range context {
+				if k > 0 {
+					labels[i] += " "
+				}
+				labels[i] += h.Labels[s]
+			}
+		}
+	}
+	chain, err := NewChain(P, labels)
+	if err != nil {
+		return nil, nil, err
+	}
+	return chain, contexts, nil
+}
+
+// higherOrderJSON is the serialized form of a HigherOrderChain
+type higherOrderJSON struct {
+	Order     int           `json:"order"`
+	NumStates int           `json:"num_states"`
+	Labels    []string      `json:"labels,omitempty"`
+	Unseen    UnseenPolicy  `json:"unseen"`
+	Contexts  []contextJSON `json:"contexts"`
+}
+
+type contextJSON struct {
+	Context []int     `json:"context"`
+	Next    []int     `json:"next"`
+	Probs   []float64 `json:"probs"`
+}
+
+// MarshalJSON encodes h with its observed contexts, in increasing order.
+func (h *HigherOrderChain) MarshalJSON() ([]byte, error) {
+	out := higherOrderJSON{
+		Order:     h.Order,
+		NumStates: h.NumStates,
+		Labels:    h.Labels,
+		Unseen:    h.Unseen,
+		Contexts:  []contextJSON{},
+	}
+	for _, context := range h.Contexts() {
+		row := h.contexts[h.key(context)]
+		out.Contexts = append(out.Contexts, contextJSON{context, row.next, row.probs})
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a chain encoded by MarshalJSON, validating it.
+func (h *HigherOrderChain) UnmarshalJSON(data []byte) error {
+	var in higherOrderJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	if in.Order < 1 || in.NumStates < 1 {
+		return fmt.Errorf("order %d and %d states are not positive", in.Order, in.NumStates)
+	}
+	if float64(in.Order)*math.Log2(float64(in.NumStates)) >= 62 {
+		return fmt.Errorf("%d states of order %d have too many contexts", in.NumStates, in.Order)
+	}
+	if in.Labels != nil && len(in.Labels) != in.NumStates {
+		return fmt.Errorf(
+			"%d labels given for a chain with %d states", len(in.Labels), in.NumStates)
+	}
+	if in.Unseen != UnseenSelfLoop && in.Unseen != UnseenUniform {
+		return fmt.Errorf("unknown unseen state policy %d", in.Unseen)
+	}
+	if len(in.Contexts) == 0 {
+		return fmt.Errorf("chain has no contexts")
+	}
+
+	decoded := HigherOrderChain{
+		Order:     in.Order,
+		NumStates: in.NumStates,
+		Labels:    in.Labels,
+		Unseen:    in.Unseen,
+		contexts:  make(map[int]*contextRow, len(in.Contexts)),
+	}
+	for _, c := range in.Contexts {
+		if len(c.Context) != in.Order {
+			return fmt.Errorf("context %v does not have %d states", c.Context, in.Order)
+		}
+		if len(c.Next) == 0 || len(c.Next) != len(c.Probs) {
+			return fmt.Errorf("context %v has %d next states and %d probabilities",
+				c.Context, len(c.Next), len(c.Probs))
+		}
+		seen := make(map[int]bool, len(c.Next))
+		for _, s := range c.Next {
+			if seen[s] {
+				return fmt.Errorf("next state %d of context %v is given twice", s, c.Context)
+			}
+			seen[s] = true
+		}
+		sum := 0.0
+		for i, s := range append(slices.Clone(c.Context), c.Next...) {
+			if s < 0 || s >= in.NumStates {
+				return fmt.Errorf("state %d is out of range for %d states", s, in.NumStates)
+			}
+			if i >= in.Order {
+				p := c.Probs[i-in.Order]
+				if p < 0 || math.IsNaN(p) {
+					return fmt.Errorf("probability %v of context %v is not non-negative",
+						p, c.Context)
+				}
+				sum += p
+			}
+		}
+		if math.Abs(sum-1) > Tolerance {
+			return fmt.Errorf("probabilities of context %v sum to %v, not 1", c.Context, sum)
+		}
+		key := decoded.key(c.Context)
+		if _, ok := decoded.contexts[key]; ok {
+			return fmt.Errorf("context %v is given twice", c.Context)
+		}
+		decoded.contexts[key] = &contextRow{
+			next:  c.Next,
+			probs: c.Probs,
+			table: newAliasTable(c.Probs),
+		}
+	}
+	*h = decoded
+	return nil
+}
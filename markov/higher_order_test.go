@@ -0,0 +1,303 @@
+package markov
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+// TestFitHigherOrder fits a second-order sequence that a first-order chain
+// cannot express: after (0, 1) comes 2, but after (2, 1) comes 0
+func TestFitHigherOrder(t *testing.T) {
+	t.Parallel()
+	sequences := [][]int{{0, 1, 2, 1, 0, 1, 2, 1, 0}}
+
+	h, err := FitHigherOrder(sequences, 2, nil)
+	if err != nil {
+		t.Fatalf("FitHigherOrder failed: %v", err)
+	}
+	if h.NumStates != 3 || h.NumContexts() != 4 {
+		t.Fatalf("Expected 3 states and 4 contexts, got %d and %d",
+			h.NumStates, h.NumContexts())
+	}
+
+	tests := []struct {
+		name    string
+		history []int
+		want    []float64
+	}{
+		{"After01", []int{0, 1}, []float64{0, 0, 1}},
+		{"After21", []int{2, 1}, []float64{1, 0, 0}},
+		{"LongHistory", []int{2, 2, 1, 2}, []float64{0, 1, 0}},
+		{"UnseenSelfLoop", []int{2, 2}, []float64{0, 0, 1}},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			got, err := h.Predict(test.history)
+			if err != nil {
+				t.Fatalf("Predict failed: %v", err)
+			}
+			if !almostEqual(got, test.want, 1e-12) {
+				t.Fatalf("Expected %v, got %v", test.want, got)
+			}
+		})
+	}
+
+	if _, err := h.Predict([]int{1}); err == nil {
+		t.Fatalf("Expected error for short history")
+	}
+	if _, err := h.Predict([]int{0, 3}); err == nil {
+		t.Fatalf("Expected error for out-of-range state")
+	}
+	if _, err := FitHigherOrder(sequences, 0, nil); err == nil {
+		t.Fatalf("Expected error for order 0")
+	}
+	if _, err := FitHigherOrder([][]int{{0, 1}}, 2, nil); err == nil {
+		t.Fatalf("Expected error for sequences no longer than the order")
+	}
+
+	smoothed, err := FitHigherOrder(sequences, 2, &FitOptions{Smoothing: 1})
+	if err != nil {
+		t.Fatalf("FitHigherOrder failed: %v", err)
+	}
+	got, _ := smoothed.Predict([]int{0, 1})
+	if !almostEqual(got, []float64{0.2, 0.2, 0.6}, 1e-12) {
+		t.Fatalf("Expected smoothed [0.2 0.2 0.6], got %v", got)
+	}
+}
+
+// TestHigherOrderSimulate checks that simulation follows the deterministic
+// second-order cycle
+func TestHigherOrderSimulate(t *testing.T) {
+	t.Parallel()
+	h, err := FitHigherOrderLabels(
+		[][]string{{"a", "b", "c", "b", "a", "b", "c", "b", "a"}}, 2, nil)
+	if err != nil {
+		t.Fatalf("FitHigherOrderLabels failed: %v", err)
+	}
+	if !reflect.DeepEqual(h.Labels, []string{"a", "b", "c"}) {
+		t.Fatalf("Expected labels [a b c], got %v", h.Labels)
+	}
+
+	path, err := h.Simulate([]int{0, 1}, 6, rand.New(rand.NewPCG(1, 1)))
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if want := []int{0, 1, 2, 1, 0, 1, 2, 1}; !reflect.DeepEqual(path, want) {
+		t.Fatalf("Expected %v, got %v", want, path)
+	}
+	if _, err := h.Simulate([]int{0}, 6, nil); err == nil {
+		t.Fatalf("Expected error for short history")
+	}
+}
+
+// TestLift compares the lifted chain with the higher-order predictions and
+// checks that its stationary distribution matches the context frequencies
+func TestLift(t *testing.T) {
+	t.Parallel()
+	h, err := FitHigherOrderLabels(
+		[][]string{{"a", "b", "c", "b", "a", "b", "c", "b", "a"}}, 2, nil)
+	if err != nil {
+		t.Fatalf("FitHigherOrderLabels failed: %v", err)
+	}
+
+	chain, contexts, err := h.Lift()
+	if err != nil {
+		t.Fatalf("Lift failed: %v", err)
+	}
+	if chain.NumStates() != 4 || len(contexts) != 4 {
+		t.Fatalf("Expected 4 lifted states, got %d", chain.NumStates())
+	}
+	for i, context := range contexts {
+		if chain.Label(i) != h.Label(context[0])+" "+h.Label(context[1]) {
+			t.Fatalf("Lifted state %d labelled %q for context %v",
+				i, chain.Label(i), context)
+		}
+		for j, next := range contexts {
+			if chain.P.Get(i, j) > 0 && context[1] != next[0] {
+				t.Fatalf("Lifted transition %v -> %v does not shift", context, next)
+			}
+		}
+	}
+
+	result, err := chain.Stationary(&StationaryOptions{Method: DirectSolve})
+	if err != nil {
+		t.Fatalf("Stationary failed: %v", err)
+	}
+	if !almostEqual(result.Pi, []float64{0.25, 0.25, 0.25, 0.25}, 1e-12) {
+		t.Fatalf("Expected uniform stationary distribution, got %v", result.Pi)
+	}
+
+	// (0, 1) and (1, 0) are observed; the step from (0, 1) to the
+	// unobserved (1, 1) stays there, as UnseenSelfLoop repeats state 1
+	h, err = FitHigherOrder([][]int{{0, 1, 1}, {0, 1, 0, 1}}, 2, nil)
+	if err != nil {
+		t.Fatalf("FitHigherOrder failed: %v", err)
+	}
+	chain, contexts, err = h.Lift()
+	if err != nil {
+		t.Fatalf("Lift failed: %v", err)
+	}
+	if !reflect.DeepEqual(contexts, [][]int{{0, 1}, {1, 0}, {1, 1}}) {
+		t.Fatalf("Expected contexts [[0 1] [1 0] [1 1]], got %v", contexts)
+	}
+	if chain.P.Get(0, 1) != 0.5 || chain.P.Get(0, 2) != 0.5 ||
+		chain.P.Get(1, 0) != 1 || chain.P.Get(2, 2) != 1 {
+		t.Fatalf("Unexpected lifted transition matrix %v", chain.P)
+	}
+}
+
+// TestLiftPredict checks that every lifted transition, including those out of
+// unobserved contexts, matches Predict under both unseen state policies
+func TestLiftPredict(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		unseen UnseenPolicy
+		states int
+	}{
+		// (0, 1) -> (1, 2), unobserved, -> (2, 2), observed, -> (2, 0),
+		// unobserved, -> (0, 0), unobserved and absorbing
+		{"SelfLoop", UnseenSelfLoop, 5},
+		// Every one of the 9 contexts is reachable
+		{"Uniform", UnseenUniform, 9},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			h, err := FitHigherOrder([][]int{{0, 1, 2}, {2, 2, 0}}, 2,
+				&FitOptions{Unseen: test.unseen})
+			if err != nil {
+				t.Fatalf("FitHigherOrder failed: %v", err)
+			}
+			chain, contexts, err := h.Lift()
+			if err != nil {
+				t.Fatalf("Lift failed: %v", err)
+			}
+			if chain.NumStates() != test.states {
+				t.Fatalf("Expected %d lifted states, got %d: %v",
+					test.states, chain.NumStates(), contexts)
+			}
+			for i, context := range contexts {
+				want, err := h.Predict(context)
+				if err != nil {
+					t.Fatalf("Predict failed: %v", err)
+				}
+				got := make([]float64, h.NumStates)
+				for j, next := range contexts {
+					if p := chain.P.Get(i, j); p > 0 {
+						if next[0] != context[1] {
+							t.Fatalf("Lifted transition %v -> %v does not shift",
+								context, next)
+						}
+						got[next[1]] = p
+					}
+				}
+				if !almostEqual(got, want, 0) {
+					t.Fatalf("Context %v: expected %v, got %v", context, want, got)
+				}
+			}
+		})
+	}
+}
+
+// TestLiftSize checks that lifting a sparse fit creates states in proportion
+// to the observed contexts, and that too many contexts are rejected
+func TestLiftSize(t *testing.T) {
+	t.Parallel()
+	rng := rand.New(rand.NewPCG(9, 10))
+	tokens := func(length, vocabulary int) []int {
+		seq := make([]int, length)
+		for i := range seq {
+			seq[i] = rng.IntN(vocabulary)
+		}
+		return seq
+	}
+
+	// Under UnseenSelfLoop each step to an unobserved context (a, b) adds
+	// at most it and (b, b)
+	seq := tokens(200, 150)
+	h, err := FitHigherOrder([][]int{seq}, 2, nil)
+	if err != nil {
+		t.Fatalf("FitHigherOrder failed: %v", err)
+	}
+	chain, _, err := h.Lift()
+	if err != nil {
+		t.Fatalf("Lift failed: %v", err)
+	}
+	if chain.NumStates() > h.NumContexts()+2*len(seq) {
+		t.Fatalf("Lifted %d observed contexts into %d states",
+			h.NumContexts(), chain.NumStates())
+	}
+
+	// Under UnseenUniform all 150^2 contexts are reachable
+	h, err = FitHigherOrder([][]int{seq}, 2, &FitOptions{Unseen: UnseenUniform})
+	if err != nil {
+		t.Fatalf("FitHigherOrder failed: %v", err)
+	}
+	if _, _, err := h.Lift(); err == nil {
+		t.Fatalf("Expected error lifting with uniform unseen contexts")
+	}
+
+	h, err = FitHigherOrder([][]int{tokens(6000, 1000)}, 2, nil)
+	if err != nil {
+		t.Fatalf("FitHigherOrder failed: %v", err)
+	}
+	if _, _, err := h.Lift(); err == nil {
+		t.Fatalf("Expected error lifting %d contexts", h.NumContexts())
+	}
+}
+
+// TestHigherOrderJSON round-trips a chain through JSON and rejects invalid
+// encodings
+func TestHigherOrderJSON(t *testing.T) {
+	t.Parallel()
+	h, err := FitHigherOrderLabels(
+		[][]string{{"a", "b", "c", "b", "a", "b", "b"}}, 2,
+		&FitOptions{Unseen: UnseenUniform})
+	if err != nil {
+		t.Fatalf("FitHigherOrderLabels failed: %v", err)
+	}
+
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded HigherOrderChain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Order != 2 || decoded.Unseen != UnseenUniform ||
+		!reflect.DeepEqual(decoded.Labels, h.Labels) ||
+		!reflect.DeepEqual(decoded.Contexts(), h.Contexts()) {
+		t.Fatalf("Decoded chain %+v differs from %+v", decoded, *h)
+	}
+	for _, context := range append(h.Contexts(), []int{2, 2}) {
+		want, _ := h.Predict(context)
+		got, _ := decoded.Predict(context)
+		if !almostEqual(got, want, 0) {
+			t.Fatalf("Context %v: expected %v, got %v", context, want, got)
+		}
+	}
+
+	for _, bad := range []string{
+		`{"order": 0, "num_states": 2, "contexts": []}`,
+		`{"order": 1, "num_states": 2, "contexts": []}`,
+		`{"order": 1, "num_states": 2, "contexts": [{"context": [0], "next": [1, 1], "probs": [0.5, 0.5]}]}`,
+		`{"order": 1, "num_states": 2, "contexts": [{"context": [0], "next": [1], "probs": [1]}, {"context": [0], "next": [0], "probs": [1]}]}`,
+		`{"order": 1, "num_states": 2, "labels": ["a"], "contexts": []}`,
+		`{"order": 1, "num_states": 2, "contexts": [{"context": [2], "next": [0], "probs": [1]}]}`,
+		`{"order": 1, "num_states": 2, "contexts": [{"context": [0], "next": [0, 1], "probs": [0.5, 0.4]}]}`,
+	} {
+		if err := json.Unmarshal([]byte(bad), &decoded); err == nil {
+			t.Fatalf("Expected error decoding %s", bad)
+		}
+	}
+}
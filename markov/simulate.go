@@ -0,0 +1,160 @@
+package markov
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/pforderique/markov_chain/linalg"
+)
+
+// aliasTable samples from a discrete distribution in O(1) with Vose's alias
+// method: pick a column uniformly, then keep it with probability prob[col] or
+// take alias[col] instead.
+type aliasTable struct {
+	prob  []float64
+	alias []int
+}
+
+// newAliasTable builds the alias table of the distribution weights, which
+// must be non-negative with a positive sum
+func newAliasTable(weights []float64) aliasTable {
+	n := len(weights)
+	sum := 0.0
+	for _, w := range weights {
+		sum += w
+	}
+
+	t := aliasTable{prob: make([]float64, n), alias: make([]int, n)}
+	scaled := make([]float64, n)
+	var small, large []int
+	for i, w := range weights {
+		scaled[i] = w * float64(n) / sum
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		s := small[len(small)-1]
+		small = small[:len(small)-1]
+		l := large[len(large)-1]
+
+		t.prob[s] = scaled[s]
+		t.alias[s] = l
+		scaled[l] -= 1 - scaled[s]
+		if scaled[l] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, l)
+		}
+	}
+	// Whatever is left is 1 up to rounding
+	for _, i := range append(small, large...) {
+		t.prob[i] = 1
+		t.alias[i] = i
+	}
+	return t
+}
+
+// sample draws an index from the table
+func (t aliasTable) sample(rng *rand.Rand) int {
+	col := rng.IntN(len(t.prob))
+	if rng.Float64() < t.prob[col] {
+		return col
+	}
+	return t.alias[col]
+}
+
+// Sampler draws trajectories of a chain. It precomputes an alias table for
+// every row of the transition matrix, so that each step costs O(1) no matter
+// how many states there are. Build one with Chain.Sampler and reuse it for
+// many trajectories.
+type Sampler struct {
+	rows []aliasTable
+}
+
+// Sampler returns a Sampler over the current transition matrix of c. Later
+// changes to c.P are not reflected in it.
+func (c *Chain) Sampler() *Sampler {
+	n := c.NumStates()
+	s := &Sampler{rows: make([]aliasTable, n)}
+	row := make([]float64, n)
+	for i := 0; i < n; i++ {
+		for j := range row {
+			row[j] = c.P.Get(i, j)
+		}
+		s.rows[i] = newAliasTable(row)
+	}
+	return s
+}
+
+// NumStates returns the number of states of the chain s samples from.
+func (s *Sampler) NumStates() int {
+	return len(s.rows)
+}
+
+// Next draws the state following state.
+func (s *Sampler) Next(state int, rng *rand.Rand) int {
+	return s.rows[state].sample(rng)
+}
+
+// Simulate returns a trajectory of steps transitions starting from start, so
+// the result holds steps+1 states. If rng is nil a randomly seeded generator
+// is used.
+func (s *Sampler) Simulate(start, steps int, rng *rand.Rand) ([]int, error) {
+	if start < 0 || start >= s.NumStates() {
+		return nil, fmt.Errorf(
+			"start state %d is out of range for chain with %d states",
+			start, s.NumStates())
+	}
+	if steps < 0 {
+		return nil, fmt.Errorf("number of steps %d is negative", steps)
+	}
+	if rng == nil {
+		rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+
+	path := make([]int, steps+1)
+	path[0] = start
+	for k := 1; k <= steps; k++ {
+		path[k] = s.Next(path[k-1], rng)
+	}
+	return path, nil
+}
+
+// SimulateMany returns count independent trajectories like Simulate, run in
+// parallel on linalg.DefaultPool. Trajectory i uses its own generator seeded
+// from (seed, i), so the result depends only on the arguments and not on how
+// the trajectories are scheduled.
+func (s *Sampler) SimulateMany(start, steps, count int, seed uint64) ([][]int, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("number of trajectories %d is negative", count)
+	}
+	// Validate once up front so that the workers cannot fail
+	if _, err := s.Simulate(start, 0, nil); err != nil {
+		return nil, err
+	}
+	if steps < 0 {
+		return nil, fmt.Errorf("number of steps %d is negative", steps)
+	}
+
+	paths := make([][]int, count)
+	linalg.DefaultPool().Run(count, func(i int) {
+		rng := rand.New(rand.NewPCG(seed, uint64(i)))
+		paths[i], _ = s.Simulate(start, steps, rng)
+	})
+	return paths, nil
+}
+
+// Simulate returns a trajectory of c of steps transitions starting from
+// start. To draw many trajectories, build a Sampler once instead.
+func (c *Chain) Simulate(start, steps int, rng *rand.Rand) ([]int, error) {
+	return c.Sampler().Simulate(start, steps, rng)
+}
+
+// SimulateMany returns count independent trajectories of c, run in parallel
+// with deterministic seeding as described on Sampler.SimulateMany.
+func (c *Chain) SimulateMany(start, steps, count int, seed uint64) ([][]int, error) {
+	return c.Sampler().SimulateMany(start, steps, count, seed)
+}
@@ -0,0 +1,133 @@
+package markov
+
+import (
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+// TestAliasTable compares the sampling frequencies of alias tables with their
+// distributions
+func TestAliasTable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		weights []float64
+	}{
+		{"Uniform", []float64{0.25, 0.25, 0.25, 0.25}},
+		{"Skewed", []float64{0.7, 0.2, 0.1}},
+		{"Zeros", []float64{0, 0.5, 0, 0.5, 0}},
+		{"Single", []float64{1}},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			table := newAliasTable(test.weights)
+			rng := rand.New(rand.NewPCG(1, 2))
+			const draws = 200000
+			counts := make([]float64, len(test.weights))
+			for k := 0; k < draws; k++ {
+				counts[table.sample(rng)]++
+			}
+			for i, w := range test.weights {
+				if w == 0 && counts[i] > 0 {
+					t.Fatalf("Sampled index %d with zero weight", i)
+				}
+				if math.Abs(counts[i]/draws-w) > 0.01 {
+					t.Fatalf("Index %d sampled with frequency %f, expected %f",
+						i, counts[i]/draws, w)
+				}
+			}
+		})
+	}
+}
+
+// TestSimulate checks that the occupation frequencies of a long trajectory
+// approach the stationary distribution, and that seeding is reproducible
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+	c := newTestChain(t, []float64{
+		0.5, 0.3, 0.2,
+		0.1, 0.8, 0.1,
+		0.3, 0.3, 0.4,
+	}, 3)
+
+	result, err := c.Stationary(nil)
+	if err != nil {
+		t.Fatalf("Stationary failed: %v", err)
+	}
+	path, err := c.Simulate(0, 200000, rand.New(rand.NewPCG(3, 4)))
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if len(path) != 200001 || path[0] != 0 {
+		t.Fatalf("Expected 200001 states starting at 0, got %d starting at %d",
+			len(path), path[0])
+	}
+	freq := make([]float64, 3)
+	for _, s := range path {
+		freq[s] += 1 / float64(len(path))
+	}
+	if !almostEqual(freq, result.Pi, 0.01) {
+		t.Fatalf("Expected frequencies near %v, got %v", result.Pi, freq)
+	}
+
+	again, _ := c.Simulate(0, 200000, rand.New(rand.NewPCG(3, 4)))
+	if !reflect.DeepEqual(path, again) {
+		t.Fatalf("Simulate with the same seed gave different trajectories")
+	}
+
+	for _, args := range [][2]int{{-1, 10}, {3, 10}, {0, -1}} {
+		if _, err := c.Simulate(args[0], args[1], nil); err == nil {
+			t.Fatalf("Expected error for start %d and steps %d", args[0], args[1])
+		}
+	}
+}
+
+// TestSimulateMany checks that parallel trajectories are reproducible and
+// follow the chain's transitions
+func TestSimulateMany(t *testing.T) {
+	t.Parallel()
+	// Deterministic cycle after leaving state 0
+	c := newTestChain(t, []float64{
+		0, 0.5, 0.5,
+		0, 0, 1,
+		0, 1, 0,
+	}, 3)
+	s := c.Sampler()
+
+	paths, err := s.SimulateMany(0, 50, 64, 42)
+	if err != nil {
+		t.Fatalf("SimulateMany failed: %v", err)
+	}
+	if len(paths) != 64 {
+		t.Fatalf("Expected 64 trajectories, got %d", len(paths))
+	}
+	firsts := map[int]bool{}
+	for _, path := range paths {
+		if len(path) != 51 {
+			t.Fatalf("Expected 51 states, got %d", len(path))
+		}
+		for k := 2; k < len(path); k++ {
+			if path[k] != 3-path[k-1] {
+				t.Fatalf("Impossible transition %d -> %d", path[k-1], path[k])
+			}
+		}
+		firsts[path[1]] = true
+	}
+	if len(firsts) != 2 {
+		t.Fatalf("Expected trajectories through both 1 and 2, got %v", firsts)
+	}
+
+	again, _ := s.SimulateMany(0, 50, 64, 42)
+	if !reflect.DeepEqual(paths, again) {
+		t.Fatalf("SimulateMany with the same seed gave different trajectories")
+	}
+	if _, err := s.SimulateMany(5, 50, 4, 42); err == nil {
+		t.Fatalf("Expected error for out-of-range start state")
+	}
+}
@@ -0,0 +1,196 @@
+package markov
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/pforderique/markov_chain/linalg"
+)
+
+// ErrReducible is returned when a chain has more than one closed
+// communicating class, so its stationary distribution is not unique.
+var ErrReducible = errors.New("chain is reducible: stationary distribution is not unique")
+
+// StationaryMethod selects the algorithm used by Chain.Stationary.
+type StationaryMethod int
+
+const (
+	// PowerIteration repeatedly steps a distribution until it stops changing.
+	PowerIteration StationaryMethod = iota
+	// DirectSolve solves the linear system π(I - P) = 0, Σπ = 1.
+	DirectSolve
+)
+
+// StationaryOptions configures Chain.Stationary. The zero value uses power
+// iteration with default tolerance and iteration limit.
+type StationaryOptions struct {
+	Method StationaryMethod
+	// Tolerance is the L1 change between iterations at which power iteration
+	// is considered converged. Defaults to 1e-12.
+	Tolerance float64
+	// MaxIterations bounds power iteration. Defaults to 100000.
+	MaxIterations int
+	// Initial is the starting distribution for power iteration, which must be
+	// non-negative and sum to 1. Defaults to the uniform distribution.
+	Initial []float64
+}
+
+// StationaryResult is the stationary distribution π with πP = π, along with
+// how it was obtained.
+type StationaryResult struct {
+	Pi []float64
+	// Iterations is the number of power iteration steps taken (0 for
+	// DirectSolve).
+	Iterations int
+	// Converged reports whether power iteration reached the tolerance.
+	Converged bool
+	// Residual is the L1 norm of πP - π.
+	Residual float64
+}
+
+// Stationary returns the stationary distribution of c. It returns
+// ErrReducible if the distribution is not unique. If power iteration runs out
+// of iterations, the last iterate is returned with Converged set to false.
+func (c *Chain) Stationary(opts *StationaryOptions) (*StationaryResult, error) {
+	if opts == nil {
+		opts = &StationaryOptions{}
+	}
+	if !c.hasUniqueClosedClass() {
+		return nil, ErrReducible
+	}
+
+	var result *StationaryResult
+	var err error
+	switch opts.Method {
+	case PowerIteration:
+		result, err = c.stationaryPower(opts)
+	case DirectSolve:
+		result, err = c.stationaryDirect()
+	default:
+		return nil, fmt.Errorf("unknown stationary method %d", opts.Method)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Residual = c.residual(result.Pi)
+	return result, nil
+}
+
+// stationaryPower iterates the lazy chain (I + P)/2, which has the same
+// stationary distribution as P but also converges when P is periodic.
+func (c *Chain) stationaryPower(opts *StationaryOptions) (*StationaryResult, error) {
+	n := c.NumStates()
+	tol := opts.Tolerance
+	if tol <= 0 {
+		tol = 1e-12
+	}
+	maxIter := opts.MaxIterations
+	if maxIter <= 0 {
+		maxIter = 100000
+	}
+
+	pi := make([]float64, n)
+	if opts.Initial != nil {
+		if len(opts.Initial) != n {
+			return nil, fmt.Errorf(
+				"initial distribution of length %d does not match chain with %d states",
+				len(opts.Initial), n)
+		}
+		if err := ValidateDistribution("initial distribution", opts.Initial); err != nil {
+			return nil, err
+		}
+		copy(pi, opts.Initial)
+	} else {
+		for i := range pi {
+			pi[i] = 1 / float64(n)
+		}
+	}
+
+	result := &StationaryResult{}
+	for result.Iterations < maxIter {
+		next := c.P.LeftMultiply(pi)
+		diff := 0.0
+		for i := range next {
+			next[i] = (next[i] + pi[i]) / 2
+			diff += math.Abs(next[i] - pi[i])
+		}
+		pi = next
+		result.Iterations++
+		if diff < tol {
+			result.Converged = true
+			break
+		}
+	}
+
+	result.Pi = normalize(pi)
+	return result, nil
+}
+
+// stationaryDirect solves (I - P)ᵀπ = 0 with the last equation replaced by
+// Σπ = 1.
+func (c *Chain) stationaryDirect() (*StationaryResult, error) {
+	n := c.NumStates()
+	A, err := linalg.NewSquareMatrix(n)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			value := -c.P.Get(j, i)
+			if i == j {
+				value += 1
+			}
+			A.Set(i, j, value)
+		}
+	}
+	b := make([]float64, n)
+	for j := 0; j < n; j++ {
+		A.Set(n-1, j, 1)
+	}
+	b[n-1] = 1
+
+	pi, err := A.Solve(b)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pi {
+		// Clean up tiny negative values introduced by rounding
+		if pi[i] < 0 {
+			pi[i] = 0
+		}
+	}
+	return &StationaryResult{Pi: normalize(pi), Converged: true}, nil
+}
+
+// hasUniqueClosedClass reports whether the chain has exactly one closed
+// communicating class, which is when its stationary distribution is unique.
+func (c *Chain) hasUniqueClosedClass() bool {
+	return len(c.Classify().RecurrentClasses()) == 1
+}
+
+// residual returns the L1 norm of πP - π
+func (c *Chain) residual(pi []float64) float64 {
+	next := c.P.LeftMultiply(pi)
+	r := 0.0
+	for i := range next {
+		r += math.Abs(next[i] - pi[i])
+	}
+	return r
+}
+
+// normalize scales v in place so that it sums to 1
+func normalize(v []float64) []float64 {
+	sum := 0.0
+	for _, x := range v {
+		sum += x
+	}
+	if sum == 0 {
+		return v
+	}
+	for i := range v {
+		v[i] /= sum
+	}
+	return v
+}
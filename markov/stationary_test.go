@@ -0,0 +1,90 @@
+package markov
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestStationary calls Chain.Stationary with both solution methods
+func TestStationary(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data []float64
+		n    int
+		want []float64
+	}{
+		{"TwoState", []float64{0.9, 0.1, 0.5, 0.5}, 2, []float64{5.0 / 6, 1.0 / 6}},
+		{"Periodic", []float64{0, 1, 1, 0}, 2, []float64{0.5, 0.5}},
+		{
+			"ThreeState",
+			[]float64{0.5, 0.25, 0.25, 0.5, 0, 0.5, 0.25, 0.25, 0.5},
+			3,
+			[]float64{0.4, 0.2, 0.4},
+		},
+		{
+			"TransientState",
+			[]float64{0.5, 0.5, 0, 0, 0.5, 0.5, 0, 0.5, 0.5},
+			3,
+			[]float64{0, 0.5, 0.5},
+		},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			c := newTestChain(t, test.data, test.n)
+			for _, method := range []StationaryMethod{PowerIteration, DirectSolve} {
+				result, err := c.Stationary(&StationaryOptions{Method: method})
+				if err != nil {
+					t.Fatalf("Method %d failed: %v", method, err)
+				}
+				if !result.Converged {
+					t.Fatalf("Method %d did not converge", method)
+				}
+				if !almostEqual(result.Pi, test.want, 1e-9) {
+					t.Fatalf("Method %d: expected %v, got %v",
+						method, test.want, result.Pi)
+				}
+			}
+		})
+	}
+
+	c := newTestChain(t, []float64{0.9, 0.1, 0.5, 0.5}, 2)
+	for _, initial := range [][]float64{{0, 0}, {-1, 1}, {1}} {
+		if _, err := c.Stationary(&StationaryOptions{Initial: initial}); err == nil {
+			t.Fatalf("Expected error for initial distribution %v", initial)
+		}
+	}
+}
+
+// TestStationaryReducible calls Chain.Stationary on a chain with two closed
+// classes
+func TestStationaryReducible(t *testing.T) {
+	t.Parallel()
+	c := newTestChain(t, []float64{1, 0, 0, 1}, 2)
+	_, err := c.Stationary(nil)
+	if !errors.Is(err, ErrReducible) {
+		t.Fatalf("Expected ErrReducible, got %v", err)
+	}
+}
+
+// TestStationaryMaxIterations checks that power iteration reports when it
+// runs out of iterations
+func TestStationaryMaxIterations(t *testing.T) {
+	t.Parallel()
+	c := newTestChain(t, []float64{0.99, 0.01, 0.01, 0.99}, 2)
+	result, err := c.Stationary(&StationaryOptions{
+		MaxIterations: 2,
+		Initial:       []float64{1, 0},
+	})
+	if err != nil {
+		t.Fatalf("Stationary failed: %v", err)
+	}
+	if result.Converged || result.Iterations != 2 {
+		t.Fatalf("Expected 2 unconverged iterations, got %d (converged %v)",
+			result.Iterations, result.Converged)
+	}
+}
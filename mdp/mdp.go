@@ -0,0 +1,196 @@
+// Package mdp implements finite Markov decision processes: Markov chains
+// whose transitions and rewards depend on an action chosen in every state.
+package mdp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/pforderique/markov_chain/linalg"
+	"github.com/pforderique/markov_chain/markov"
+)
+
+// ErrMultichain is returned by the average-reward solvers when a policy's
+// chain has more than one closed class, so no single gain describes it.
+var ErrMultichain = errors.New("policy has more than one closed class")
+
+// MDP is a Markov decision process over n states and a actions. P[k].Get(i,
+// j) is the probability of moving from state i to state j when taking
+// action k, and R.Get(i, k) is the expected reward for taking action k in
+// state i.
+type MDP struct {
+	P []*linalg.SquareMatrix
+	R *linalg.Matrix
+}
+
+// New validates every P[k] as a row-stochastic matrix over the same states,
+// within markov.Tolerance, and R as an n×a reward matrix, and returns an MDP
+// over them.
+func New(P []*linalg.SquareMatrix, R *linalg.Matrix) (*MDP, error) {
+	if len(P) == 0 {
+		return nil, fmt.Errorf("no actions given")
+	}
+	if R == nil {
+		return nil, fmt.Errorf("reward matrix is nil")
+	}
+	for k, Pk := range P {
+		if Pk == nil {
+			return nil, fmt.Errorf("transition matrix of action %d is nil", k)
+		}
+	}
+	n := P[0].N()
+	if dims := R.Dims(); len(dims) != 2 || dims[0] != n || dims[1] != len(P) {
+		return nil, &linalg.ErrDimensionMismatch{Op: "mdp.New", A: []int{n, len(P)}, B: dims}
+	}
+
+	for k, Pk := range P {
+		if Pk.N() != n {
+			return nil, &linalg.ErrDimensionMismatch{
+				Op: "mdp.New", A: []int{n, n}, B: []int{Pk.N(), Pk.N()}}
+		}
+		if err := markov.ValidateStochastic(Pk); err != nil {
+			return nil, fmt.Errorf("action %d: %w", k, err)
+		}
+		for i := 0; i < n; i++ {
+			if r := R.Get(i, k); math.IsNaN(r) || math.IsInf(r, 0) {
+				return nil, fmt.Errorf("reward R[%d][%d] = %v is not finite", i, k, r)
+			}
+		}
+	}
+	return &MDP{P, R}, nil
+}
+
+// NumStates returns the number of states of m.
+func (m *MDP) NumStates() int {
+	return m.P[0].N()
+}
+
+// NumActions returns the number of actions of m.
+func (m *MDP) NumActions() int {
+	return len(m.P)
+}
+
+// Criterion selects what Solve optimizes.
+type Criterion int
+
+const (
+	// Discounted maximizes the expected sum of rewards discounted by
+	// SolveOptions.Discount per step.
+	Discounted Criterion = iota
+	// AverageReward maximizes the long-run expected reward per step. The
+	// solvers assume every policy they visit is unichain, with a single
+	// closed class.
+	AverageReward
+)
+
+// Method selects the algorithm used by Solve.
+type Method int
+
+const (
+	// ValueIteration repeatedly applies the Bellman optimality update.
+	ValueIteration Method = iota
+	// PolicyIteration alternates exact policy evaluation, by a linear solve,
+	// with greedy policy improvement.
+	PolicyIteration
+	// ModifiedPolicyIteration evaluates each policy approximately with a
+	// fixed number of sweeps before improving it.
+	ModifiedPolicyIteration
+)
+
+// SolveOptions configures Solve. The zero value runs value iteration with
+// discount factor 0, which only maximizes the immediate reward.
+type SolveOptions struct {
+	Method    Method
+	Criterion Criterion
+	// Discount is the per-step discount factor in [0, 1) for Discounted.
+	Discount float64
+	// Tolerance is the size of the Bellman update at which value iteration
+	// and modified policy iteration stop: the largest change in values for
+	// Discounted, and the span of the change for AverageReward. Defaults to
+	// 1e-9.
+	Tolerance float64
+	// MaxIterations bounds the number of iterations. Defaults to 100000.
+	MaxIterations int
+	// Sweeps is the number of evaluation sweeps per iteration of modified
+	// policy iteration. Defaults to 20.
+	Sweeps int
+}
+
+// Solution is an optimal policy and its values, along with how they were
+// obtained.
+type Solution struct {
+	// Policy[i] is the action to take in state i.
+	Policy []int
+	// Values are the expected discounted rewards from each state for
+	// Discounted, and the relative values (bias) normalized to 0 in state 0
+	// for AverageReward.
+	Values []float64
+	// Gain is the long-run reward per step for AverageReward, and 0 for
+	// Discounted.
+	Gain float64
+	// Iterations is the number of value updates or policy improvements.
+	Iterations int
+	// Converged reports whether the tolerance was reached, or for policy
+	// iteration whether the policy stopped changing.
+	Converged bool
+	// Residual is the size of the last Bellman update, measured as for
+	// SolveOptions.Tolerance.
+	Residual float64
+}
+
+// Solve finds an optimal stationary policy of m. If the iteration limit is
+// reached, the last policy is returned with Converged set to false.
+func (m *MDP) Solve(opts *SolveOptions) (*Solution, error) {
+	if opts == nil {
+		opts = &SolveOptions{}
+	}
+	s, err := newSolver(m, opts)
+	if err != nil {
+		return nil, err
+	}
+	if s.tolerance == 0 {
+		s.tolerance = 1e-9
+	}
+	if s.maxIter == 0 {
+		s.maxIter = 100000
+	}
+	if s.sweeps == 0 {
+		s.sweeps = 20
+	}
+
+	switch opts.Method {
+	case ValueIteration:
+		return s.valueIteration(), nil
+	case PolicyIteration:
+		return s.policyIteration()
+	case ModifiedPolicyIteration:
+		return s.modifiedPolicyIteration(), nil
+	default:
+		return nil, fmt.Errorf("unknown solve method %d", opts.Method)
+	}
+}
+
+// Evaluate returns the values of following policy under the criterion of
+// opts, along with its gain for AverageReward. Only opts.Criterion and
+// opts.Discount are used.
+func (m *MDP) Evaluate(policy []int, opts *SolveOptions) ([]float64, float64, error) {
+	if opts == nil {
+		opts = &SolveOptions{}
+	}
+	if len(policy) != m.NumStates() {
+		return nil, 0, fmt.Errorf(
+			"policy of length %d does not match %d states", len(policy), m.NumStates())
+	}
+	for i, k := range policy {
+		if k < 0 || k >= m.NumActions() {
+			return nil, 0, fmt.Errorf(
+				"action %d in state %d is out of range for %d actions", k, i, m.NumActions())
+		}
+	}
+	s, err := newSolver(m, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.evaluate(policy)
+}
@@ -0,0 +1,137 @@
+package mdp
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/pforderique/markov_chain/linalg"
+)
+
+func newTestMDP(t *testing.T, P [][][]float64, R [][]float64) *MDP {
+	t.Helper()
+	matrices := make([]*linalg.SquareMatrix, len(P))
+	for k, rows := range P {
+		var err error
+		if matrices[k], err = linalg.FromRows(rows); err != nil {
+			t.Fatalf("Failed to create transition matrix: %v", err)
+		}
+	}
+	data := []float64{}
+	for _, row := range R {
+		data = append(data, row...)
+	}
+	rewards, err := linalg.NewMatrixFromData(data, []int{len(R), len(R[0])})
+	if err != nil {
+		t.Fatalf("Failed to create reward matrix: %v", err)
+	}
+	m, err := New(matrices, rewards)
+	if err != nil {
+		t.Fatalf("Failed to create MDP: %v", err)
+	}
+	return m
+}
+
+// randomMDP returns an MDP with n states, a actions, positive transition
+// probabilities and rewards in [0, 10)
+func randomMDP(t *testing.T, n, a int, rng *rand.Rand) *MDP {
+	P := make([][][]float64, a)
+	for k := range P {
+		P[k] = make([][]float64, n)
+		for i := range P[k] {
+			P[k][i] = make([]float64, n)
+			sum := 0.0
+			for j := range P[k][i] {
+				P[k][i][j] = 0.01 + rng.Float64()
+				sum += P[k][i][j]
+			}
+			for j := range P[k][i] {
+				P[k][i][j] /= sum
+			}
+		}
+	}
+	R := make([][]float64, n)
+	for i := range R {
+		R[i] = make([]float64, a)
+		for k := range R[i] {
+			R[i][k] = 10 * rng.Float64()
+		}
+	}
+	return newTestMDP(t, P, R)
+}
+
+// TestNew calls New with valid and invalid parameters
+func TestNew(t *testing.T) {
+	t.Parallel()
+	stay, _ := linalg.Identity(2)
+	swap, _ := linalg.FromRows([][]float64{{0, 1}, {1, 0}})
+	bad, _ := linalg.FromRows([][]float64{{0.5, 0.6}, {1, 0}})
+	three, _ := linalg.Identity(3)
+	R, _ := linalg.NewMatrixFromData([]float64{1, 0, 2, 0}, []int{2, 2})
+	R1, _ := linalg.NewMatrixFromData([]float64{1, 2}, []int{2, 1})
+
+	tests := []struct {
+		name    string
+		P       []*linalg.SquareMatrix
+		R       *linalg.Matrix
+		wantErr bool
+	}{
+		{"Valid", []*linalg.SquareMatrix{stay, swap}, R, false},
+		{"NoActions", nil, R, true},
+		{"RewardShape", []*linalg.SquareMatrix{stay, swap}, R1, true},
+		{"NotStochastic", []*linalg.SquareMatrix{stay, bad}, R, true},
+		{"StateMismatch", []*linalg.SquareMatrix{stay, three}, R, true},
+		{"NilFirstAction", []*linalg.SquareMatrix{nil, stay}, R, true},
+		{"NilLaterAction", []*linalg.SquareMatrix{stay, nil}, R, true},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			_, err := New(test.P, test.R)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Expected error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestEvaluate evaluates fixed policies against closed forms
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+	// Action 0 stays and earns the state's reward; action 1 moves to the
+	// other state and earns nothing
+	m := newTestMDP(t,
+		[][][]float64{{{1, 0}, {0, 1}}, {{0, 1}, {1, 0}}},
+		[][]float64{{1, 0}, {2, 0}})
+
+	values, _, err := m.Evaluate([]int{1, 0}, &SolveOptions{Discount: 0.9})
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if math.Abs(values[0]-18) > 1e-9 || math.Abs(values[1]-20) > 1e-9 {
+		t.Fatalf("Expected values [18 20], got %v", values)
+	}
+
+	values, gain, err := m.Evaluate([]int{1, 0}, &SolveOptions{Criterion: AverageReward})
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if math.Abs(gain-2) > 1e-12 || values[0] != 0 || math.Abs(values[1]-2) > 1e-12 {
+		t.Fatalf("Expected gain 2 and relative values [0 2], got %v and %v", gain, values)
+	}
+
+	// Staying in both states leaves two closed classes
+	_, _, err = m.Evaluate([]int{0, 0}, &SolveOptions{Criterion: AverageReward})
+	if !errors.Is(err, ErrMultichain) {
+		t.Fatalf("Expected ErrMultichain, got %v", err)
+	}
+	if _, _, err := m.Evaluate([]int{0, 2}, &SolveOptions{Discount: 0.9}); err == nil {
+		t.Fatalf("Expected error for out-of-range action")
+	}
+	if _, _, err := m.Evaluate([]int{0, 0}, &SolveOptions{Discount: 1}); err == nil {
+		t.Fatalf("Expected error for discount factor 1")
+	}
+}
@@ -0,0 +1,254 @@
+package mdp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/pforderique/markov_chain/linalg"
+)
+
+// solver holds the parameters of one Solve call, with the transition and
+// reward matrices copied into slices for the inner loops
+type solver struct {
+	*MDP
+	n         int
+	average   bool
+	discount  float64
+	tolerance float64
+	maxIter   int
+	sweeps    int
+
+	p [][]float64 // p[k][i*n+j] = P[k].Get(i, j)
+	r [][]float64 // r[k][i] = R.Get(i, k)
+}
+
+// newSolver validates the criterion and discount factor of opts and returns
+// a solver for m with the remaining options copied as given
+func newSolver(m *MDP, opts *SolveOptions) (*solver, error) {
+	s := &solver{
+		MDP:       m,
+		n:         m.NumStates(),
+		discount:  opts.Discount,
+		tolerance: opts.Tolerance,
+		maxIter:   opts.MaxIterations,
+		sweeps:    opts.Sweeps,
+	}
+	switch opts.Criterion {
+	case Discounted:
+		if s.discount < 0 || s.discount >= 1 || math.IsNaN(s.discount) {
+			return nil, fmt.Errorf("discount factor %v is not in [0, 1)", s.discount)
+		}
+	case AverageReward:
+		s.average, s.discount = true, 1
+	default:
+		return nil, fmt.Errorf("unknown criterion %d", opts.Criterion)
+	}
+	s.load()
+	return s, nil
+}
+
+// load copies the transition and reward matrices
+func (s *solver) load() {
+	s.p = make([][]float64, s.NumActions())
+	s.r = make([][]float64, s.NumActions())
+	for k, Pk := range s.P {
+		s.p[k] = make([]float64, s.n*s.n)
+		s.r[k] = make([]float64, s.n)
+		for i := 0; i < s.n; i++ {
+			for j := 0; j < s.n; j++ {
+				s.p[k][i*s.n+j] = Pk.Get(i, j)
+			}
+			s.r[k][i] = s.R.Get(i, k)
+		}
+	}
+}
+
+// q returns the value of taking action k in state i and following values v
+// afterwards: R[i][k] + γ Σ_j P[k][i][j] v[j], with γ = 1 for AverageReward
+func (s *solver) q(i, k int, v []float64) float64 {
+	row := s.p[k][i*s.n : (i+1)*s.n]
+	sum := 0.0
+	for j, p := range row {
+		sum += p * v[j]
+	}
+	return s.r[k][i] + s.discount*sum
+}
+
+// bellman applies the Bellman optimality update to v, returning the updated
+// values and the greedy policy, which takes the lowest-numbered best action
+func (s *solver) bellman(v []float64) ([]float64, []int) {
+	next := make([]float64, s.n)
+	policy := make([]int, s.n)
+	for i := 0; i < s.n; i++ {
+		next[i] = math.Inf(-1)
+		for k := range s.p {
+			if q := s.q(i, k, v); q > next[i] {
+				next[i], policy[i] = q, k
+			}
+		}
+	}
+	return next, policy
+}
+
+// improve returns the greedy policy for v, keeping the action of policy
+// wherever no action is strictly better, so that policy iteration cannot
+// cycle between equally good policies
+func (s *solver) improve(v []float64, policy []int) []int {
+	next := slices.Clone(policy)
+	for i := 0; i < s.n; i++ {
+		best := s.q(i, policy[i], v)
+		for k := range s.p {
+			if q := s.q(i, k, v); q > best+1e-12*(1+math.Abs(best)) {
+				best, next[i] = q, k
+			}
+		}
+	}
+	return next
+}
+
+// residual measures the update d = Tv - v: its largest magnitude for
+// Discounted, or its span for AverageReward, along with the midpoint of its
+// range, which estimates the gain for AverageReward
+func (s *solver) residual(d []float64) (float64, float64) {
+	lo, hi := slices.Min(d), slices.Max(d)
+	if s.average {
+		return hi - lo, (hi + lo) / 2
+	}
+	return math.Max(math.Abs(lo), math.Abs(hi)), 0
+}
+
+// relax moves v towards tv. For Discounted it is a full step. For
+// AverageReward it is a half step, v + (tv - v)/2, which is the update of
+// the chain (I + P)/2 with the same optimal policies and relative values
+// but is guaranteed to converge for periodic chains too; the values are
+// then shifted to be 0 in state 0.
+func (s *solver) relax(v, tv []float64) {
+	if !s.average {
+		copy(v, tv)
+		return
+	}
+	for i := range v {
+		v[i] += (tv[i] - v[i]) / 2
+	}
+	shift := v[0]
+	for i := range v {
+		v[i] -= shift
+	}
+}
+
+func (s *solver) valueIteration() *Solution {
+	sol := &Solution{Values: make([]float64, s.n)}
+	for sol.Iterations < s.maxIter {
+		tv, policy := s.bellman(sol.Values)
+		d := make([]float64, s.n)
+		for i := range d {
+			d[i] = tv[i] - sol.Values[i]
+		}
+		sol.Policy = policy
+		sol.Residual, sol.Gain = s.residual(d)
+		sol.Iterations++
+		if sol.Residual < s.tolerance {
+			sol.Converged = true
+			break
+		}
+		s.relax(sol.Values, tv)
+	}
+	return sol
+}
+
+func (s *solver) modifiedPolicyIteration() *Solution {
+	sol := &Solution{Values: make([]float64, s.n)}
+	d := make([]float64, s.n)
+	for sol.Iterations < s.maxIter {
+		tv, policy := s.bellman(sol.Values)
+		for i := range d {
+			d[i] = tv[i] - sol.Values[i]
+		}
+		sol.Policy = policy
+		sol.Residual, sol.Gain = s.residual(d)
+		sol.Iterations++
+		if sol.Residual < s.tolerance {
+			sol.Converged = true
+			break
+		}
+
+		// Partial evaluation of the greedy policy
+		s.relax(sol.Values, tv)
+		for sweep := 1; sweep < s.sweeps; sweep++ {
+			for i := 0; i < s.n; i++ {
+				tv[i] = s.q(i, policy[i], sol.Values)
+			}
+			s.relax(sol.Values, tv)
+		}
+	}
+	return sol
+}
+
+func (s *solver) policyIteration() (*Solution, error) {
+	_, policy := s.bellman(make([]float64, s.n))
+	sol := &Solution{}
+	for sol.Iterations < s.maxIter {
+		values, gain, err := s.evaluate(policy)
+		if err != nil {
+			return nil, err
+		}
+		sol.Policy, sol.Values, sol.Gain = policy, values, gain
+		sol.Iterations++
+
+		next := s.improve(values, policy)
+		if slices.Equal(next, policy) {
+			sol.Converged = true
+			break
+		}
+		policy = next
+	}
+
+	tv, _ := s.bellman(sol.Values)
+	d := make([]float64, s.n)
+	for i := range d {
+		d[i] = tv[i] - sol.Values[i]
+	}
+	sol.Residual, _ = s.residual(d)
+	return sol, nil
+}
+
+// evaluate returns the values of policy by a linear solve. For Discounted it
+// solves (I - γP_π)v = r_π. For AverageReward it solves g + h = r_π + P_π h
+// with h[0] = 0, putting the gain g in place of the unknown h[0].
+func (s *solver) evaluate(policy []int) ([]float64, float64, error) {
+	n := s.n
+	data := make([]float64, n*n)
+	b := make([]float64, n)
+	for i, k := range policy {
+		row := data[i*n : (i+1)*n]
+		for j, p := range s.p[k][i*n : (i+1)*n] {
+			row[j] = -s.discount * p
+		}
+		row[i]++
+		if s.average {
+			row[0] = 1
+		}
+		b[i] = s.r[k][i]
+	}
+
+	A, err := linalg.NewSquareMatrixFromData(data, n)
+	if err != nil {
+		return nil, 0, err
+	}
+	x, err := A.Solve(b)
+	var singular *linalg.ErrSingular
+	if s.average && errors.As(err, &singular) {
+		return nil, 0, fmt.Errorf("%w: cannot evaluate policy %v", ErrMultichain, policy)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if !s.average {
+		return x, 0, nil
+	}
+	gain := x[0]
+	x[0] = 0
+	return x, gain, nil
+}
@@ -0,0 +1,149 @@
+package mdp
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// bestPolicy evaluates every deterministic policy of m and returns the
+// optimal values (or gain) and policy
+func bestPolicy(t *testing.T, m *MDP, opts *SolveOptions) ([]int, []float64, float64) {
+	n, a := m.NumStates(), m.NumActions()
+	policy := make([]int, n)
+	var best []int
+	var bestValues []float64
+	bestGain := math.Inf(-1)
+	for {
+		values, gain, err := m.Evaluate(policy, opts)
+		if err != nil {
+			t.Fatalf("Evaluate failed: %v", err)
+		}
+		better := best == nil
+		if opts.Criterion == AverageReward {
+			better = better || gain > bestGain+1e-12
+		} else if !better {
+			// The optimal policy dominates every other in every state
+			better = values[0] > bestValues[0]+1e-12
+		}
+		if better {
+			best, bestValues, bestGain = slices.Clone(policy), values, gain
+		}
+
+		i := 0
+		for ; i < n && policy[i] == a-1; i++ {
+			policy[i] = 0
+		}
+		if i == n {
+			return best, bestValues, bestGain
+		}
+		policy[i]++
+	}
+}
+
+// TestSolve compares every method with exhaustive search over the policies
+// of random MDPs
+func TestSolve(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts SolveOptions
+	}{
+		{"ValueDiscounted", SolveOptions{Method: ValueIteration, Discount: 0.95}},
+		{"PolicyDiscounted", SolveOptions{Method: PolicyIteration, Discount: 0.95}},
+		{"ModifiedDiscounted", SolveOptions{Method: ModifiedPolicyIteration, Discount: 0.95}},
+		{"ValueAverage", SolveOptions{Method: ValueIteration, Criterion: AverageReward}},
+		{"PolicyAverage", SolveOptions{Method: PolicyIteration, Criterion: AverageReward}},
+		{"ModifiedAverage", SolveOptions{Method: ModifiedPolicyIteration, Criterion: AverageReward}},
+	}
+
+	for _, test := range tests {
+		testname := test.name
+		t.Run(testname, func(t *testing.T) {
+			t.Parallel()
+			rng := rand.New(rand.NewPCG(11, 12))
+			for trial := 0; trial < 5; trial++ {
+				m := randomMDP(t, 5, 3, rng)
+				wantPolicy, wantValues, wantGain := bestPolicy(t, m, &test.opts)
+
+				sol, err := m.Solve(&test.opts)
+				if err != nil {
+					t.Fatalf("Solve failed: %v", err)
+				}
+				if !sol.Converged {
+					t.Fatalf("Expected convergence, residual %g after %d iterations",
+						sol.Residual, sol.Iterations)
+				}
+				if !slices.Equal(sol.Policy, wantPolicy) {
+					t.Fatalf("Trial %d: expected policy %v, got %v",
+						trial, wantPolicy, sol.Policy)
+				}
+				for i, v := range wantValues {
+					if math.Abs(sol.Values[i]-v) > 1e-6 {
+						t.Fatalf("Trial %d: expected values %v, got %v",
+							trial, wantValues, sol.Values)
+					}
+				}
+				if math.Abs(sol.Gain-wantGain) > 1e-6 {
+					t.Fatalf("Trial %d: expected gain %f, got %f", trial, wantGain, sol.Gain)
+				}
+			}
+		})
+	}
+}
+
+// TestSolvePeriodic solves an average-reward MDP whose chains are periodic,
+// where plain value iteration would oscillate
+func TestSolvePeriodic(t *testing.T) {
+	t.Parallel()
+	// Both actions swap states; action 1 earns more in state 1
+	m := newTestMDP(t,
+		[][][]float64{{{0, 1}, {1, 0}}, {{0, 1}, {1, 0}}},
+		[][]float64{{4, 0}, {1, 3}})
+
+	for _, method := range []Method{ValueIteration, PolicyIteration, ModifiedPolicyIteration} {
+		sol, err := m.Solve(&SolveOptions{Method: method, Criterion: AverageReward})
+		if err != nil {
+			t.Fatalf("Method %d failed: %v", method, err)
+		}
+		if !sol.Converged || !slices.Equal(sol.Policy, []int{0, 1}) ||
+			math.Abs(sol.Gain-3.5) > 1e-9 || math.Abs(sol.Values[1]+0.5) > 1e-9 {
+			t.Fatalf("Method %d: expected policy [0 1], gain 3.5 and values [0 -0.5], got %+v",
+				method, sol)
+		}
+	}
+}
+
+// TestSolveErrors checks invalid options and multichain policies
+func TestSolveErrors(t *testing.T) {
+	t.Parallel()
+	m := newTestMDP(t,
+		[][][]float64{{{1, 0}, {0, 1}}},
+		[][]float64{{1}, {2}})
+
+	sol, err := m.Solve(nil)
+	if err != nil || sol.Values[0] != 1 || sol.Values[1] != 2 {
+		t.Fatalf("Expected immediate rewards [1 2] as values, got %+v and %v", sol, err)
+	}
+	if _, err := m.Solve(&SolveOptions{Discount: -0.5}); err == nil {
+		t.Fatalf("Expected error for negative discount factor")
+	}
+	if _, err := m.Solve(&SolveOptions{Method: 5, Discount: 0.5}); err == nil {
+		t.Fatalf("Expected error for unknown method")
+	}
+	_, err = m.Solve(&SolveOptions{Method: PolicyIteration, Criterion: AverageReward})
+	if !errors.Is(err, ErrMultichain) {
+		t.Fatalf("Expected ErrMultichain, got %v", err)
+	}
+
+	sol, err = m.Solve(&SolveOptions{Discount: 0.99, MaxIterations: 3})
+	if err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	if sol.Converged || sol.Iterations != 3 {
+		t.Fatalf("Expected 3 iterations without convergence, got %+v", sol)
+	}
+}